@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// client - минимальный клиент API merchio
+type client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// call выполняет запрос и возвращает код ответа, тело ответа читается до конца,
+// чтобы соединение вернулось в пул
+func (c *client) call(ctx context.Context, method, path, token string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, err
+}
+
+func (c *client) auth(ctx context.Context, username, password string) (string, int, error) {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	status, err := c.call(ctx, http.MethodPost, "/api/auth", "",
+		map[string]string{"username": username, "password": password}, &resp)
+	return resp.Token, status, err
+}
+
+func (c *client) info(ctx context.Context, token string) (int, error) {
+	return c.call(ctx, http.MethodGet, "/api/info", token, nil, nil)
+}
+
+func (c *client) sendCoin(ctx context.Context, token, toUser string, amount int) (int, error) {
+	return c.call(ctx, http.MethodPost, "/api/sendCoin", token,
+		map[string]interface{}{"toUser": toUser, "amount": amount}, nil)
+}
+
+func (c *client) buy(ctx context.Context, token, item string) (int, error) {
+	return c.call(ctx, http.MethodGet, "/api/buy/"+url.PathEscape(item), token, nil, nil)
+}
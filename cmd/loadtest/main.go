@@ -0,0 +1,190 @@
+// Команда loadtest создаёт синтетических пользователей через /api/auth и нагружает сервис
+// смесью запросов /api/info, /api/sendCoin и /api/buy, печатая перцентили задержек и долю ошибок.
+//
+//	go run ./cmd/loadtest -addr http://localhost:8080 -users 100 -duration 30s -concurrency 50 -mix info=60,send=30,buy=10
+//
+// Сервис нужно запускать с RATE_LIMIT_ANONYMOUS=off и RATE_LIMIT_USER=off, иначе ограничитель
+// частоты отвечает 429 раньше, чем нагрузка дойдёт до базы.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// results - JSON-отчёт: создание пользователей и основная нагрузка отдельно
+type results struct {
+	Setup Report `json:"setup"`
+	Load  Report `json:"load"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "loadtest:", err)
+		os.Exit(1)
+	}
+}
+
+// errThreshold - прогон завершился, но не уложился в пороги
+var errThreshold = errors.New("thresholds not met")
+
+// defaultPassword должен проходить проверку сложности пароля при регистрации, иначе /api/auth отвечает 400
+const defaultPassword = "loadtest-password1"
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	addr := fs.String("addr", "http://localhost:8080", "base URL of the service")
+	users := fs.Int("users", 50, "number of synthetic users, at least 2")
+	password := fs.String("password", defaultPassword, "password of synthetic users")
+	prefix := fs.String("prefix", fmt.Sprintf("load-%d", time.Now().Unix()), "username prefix, reuse it to reuse existing users")
+	duration := fs.Duration("duration", 30*time.Second, "how long to generate load")
+	concurrency := fs.Int("concurrency", 20, "number of concurrent workers")
+	rate := fs.Float64("rate", 0, "target requests per second, 0 sends as fast as workers allow")
+	mixFlag := fs.String("mix", "info=60,send=30,buy=10", "operation weights")
+	items := fs.String("items", "pen,socks,cup", "comma-separated items to buy")
+	amount := fs.Int("amount", 1, "coins per transfer")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout of a single request")
+	jsonPath := fs.String("json", "", "write the JSON report to this file, - for stdout instead of the text report")
+	maxErrorRate := fs.Float64("max-error-rate", 0.01, "fail if the load error rate exceeds this fraction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := options{
+		users:       *users,
+		password:    *password,
+		prefix:      *prefix,
+		duration:    *duration,
+		rate:        *rate,
+		concurrency: *concurrency,
+		amount:      *amount,
+		timeout:     *timeout,
+	}
+	var problems []string
+	var err error
+	if opts.mix, err = parseMix(*mixFlag); err != nil {
+		problems = append(problems, "-mix: "+err.Error())
+	}
+	for _, item := range strings.Split(*items, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			opts.items = append(opts.items, item)
+		}
+	}
+	if len(opts.items) == 0 {
+		problems = append(problems, "-items: at least one item is required")
+	}
+	if opts.users < 2 {
+		problems = append(problems, "-users: at least 2 users are required for transfers")
+	}
+	if opts.concurrency < 1 {
+		problems = append(problems, "-concurrency: must be positive")
+	}
+	if opts.duration <= 0 {
+		problems = append(problems, "-duration: must be positive")
+	}
+	if opts.rate < 0 {
+		problems = append(problems, "-rate: must not be negative")
+	}
+	if opts.amount < 1 {
+		problems = append(problems, "-amount: must be positive")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	// Ctrl-C завершает нагрузку досрочно, отчёт всё равно печатается
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &client{
+		baseURL: strings.TrimRight(*addr, "/"),
+		http: &http.Client{Transport: &http.Transport{
+			MaxIdleConns:        opts.concurrency,
+			MaxIdleConnsPerHost: opts.concurrency,
+		}},
+	}
+
+	res, err := loadtest(ctx, c, opts)
+	if err != nil {
+		return err
+	}
+
+	text := stdout
+	if *jsonPath == "-" {
+		text = os.Stderr
+	}
+	fmt.Fprintln(text, "setup:")
+	if err := res.Setup.WriteText(text); err != nil {
+		return err
+	}
+	fmt.Fprintln(text, "\nload:")
+	if err := res.Load.WriteText(text); err != nil {
+		return err
+	}
+	if err := writeJSON(*jsonPath, stdout, res); err != nil {
+		return err
+	}
+
+	return checkThresholds(res.Load, *maxErrorRate)
+}
+
+// loadtest создаёт пользователей и гоняет нагрузку
+func loadtest(ctx context.Context, c *client, opts options) (*results, error) {
+	setupRec := newRecorder()
+	start := time.Now()
+	users, err := setup(ctx, c, opts, setupRec)
+	if err != nil {
+		return nil, fmt.Errorf("create users: %w", err)
+	}
+	res := &results{Setup: setupRec.report(time.Since(start))}
+
+	loadRec := newRecorder()
+	start = time.Now()
+	dropped := drive(ctx, c, opts, users, loadRec)
+	res.Load = loadRec.report(time.Since(start))
+	res.Load.TargetRPS = opts.rate
+	res.Load.Dropped = dropped
+	return res, nil
+}
+
+func writeJSON(path string, stdout io.Writer, res *results) error {
+	switch path {
+	case "":
+		return nil
+	case "-":
+		return encodeJSON(stdout, res)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encodeJSON(f, res); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// checkThresholds - доля ошибок не выше порога, при заданной частоте достигнуто не меньше 95% от неё
+func checkThresholds(rep Report, maxErrorRate float64) error {
+	var problems []string
+	if rep.ErrorRate > maxErrorRate {
+		problems = append(problems, fmt.Sprintf("error rate %.2f%% exceeds %.2f%%", rep.ErrorRate*100, maxErrorRate*100))
+	}
+	if rep.TargetRPS > 0 && rep.RPS < rep.TargetRPS*0.95 {
+		problems = append(problems, fmt.Sprintf("achieved %.1f rps of target %.1f", rep.RPS, rep.TargetRPS))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", errThreshold, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// recorder собирает результаты запросов по операциям, безопасен для конкурентного использования
+type recorder struct {
+	mu  sync.Mutex
+	ops map[string]*opSamples
+}
+
+type opSamples struct {
+	latencies []time.Duration
+	statuses  map[string]int
+	errors    int
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: make(map[string]*opSamples)}
+}
+
+// record учитывает запрос: ошибкой считается сбой транспорта или код не из 2xx
+func (r *recorder) record(op string, latency time.Duration, status int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.ops[op]
+	if !ok {
+		s = &opSamples{statuses: make(map[string]int)}
+		r.ops[op] = s
+	}
+	s.latencies = append(s.latencies, latency)
+	key := strconv.Itoa(status)
+	if err != nil {
+		key = "transport"
+	}
+	s.statuses[key]++
+	if err != nil || status < 200 || status > 299 {
+		s.errors++
+	}
+}
+
+// Report - итог прогона, он же формат JSON-отчёта. Задержки - в миллисекундах.
+type Report struct {
+	DurationSeconds float64    `json:"duration_seconds"`
+	TargetRPS       float64    `json:"target_rps,omitempty"`
+	Requests        int        `json:"requests"`
+	Errors          int        `json:"errors"`
+	ErrorRate       float64    `json:"error_rate"`
+	RPS             float64    `json:"rps"`
+	Dropped         int        `json:"dropped,omitempty"`
+	Operations      []OpReport `json:"operations"`
+}
+
+type OpReport struct {
+	Name      string         `json:"name"`
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	ErrorRate float64        `json:"error_rate"`
+	RPS       float64        `json:"rps"`
+	P50       float64        `json:"p50_ms"`
+	P90       float64        `json:"p90_ms"`
+	P95       float64        `json:"p95_ms"`
+	P99       float64        `json:"p99_ms"`
+	Max       float64        `json:"max_ms"`
+	Statuses  map[string]int `json:"statuses"`
+}
+
+func (r *recorder) report(elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rep := Report{DurationSeconds: elapsed.Seconds()}
+	for name, s := range r.ops {
+		sorted := append([]time.Duration(nil), s.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		op := OpReport{
+			Name:      name,
+			Requests:  len(sorted),
+			Errors:    s.errors,
+			ErrorRate: ratio(s.errors, len(sorted)),
+			RPS:       float64(len(sorted)) / elapsed.Seconds(),
+			P50:       millis(percentile(sorted, 50)),
+			P90:       millis(percentile(sorted, 90)),
+			P95:       millis(percentile(sorted, 95)),
+			P99:       millis(percentile(sorted, 99)),
+			Max:       millis(percentile(sorted, 100)),
+			Statuses:  s.statuses,
+		}
+		rep.Operations = append(rep.Operations, op)
+		rep.Requests += op.Requests
+		rep.Errors += op.Errors
+	}
+	sort.Slice(rep.Operations, func(i, j int) bool { return rep.Operations[i].Name < rep.Operations[j].Name })
+	rep.ErrorRate = ratio(rep.Errors, rep.Requests)
+	rep.RPS = float64(rep.Requests) / elapsed.Seconds()
+	return rep
+}
+
+// percentile - по ближайшему рангу, sorted отсортирован по возрастанию
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func (rep Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "duration %.1fs, %d requests, %.1f rps", rep.DurationSeconds, rep.Requests, rep.RPS)
+	if rep.TargetRPS > 0 {
+		fmt.Fprintf(w, " (target %.1f)", rep.TargetRPS)
+	}
+	fmt.Fprintf(w, ", errors %d (%.2f%%)\n", rep.Errors, rep.ErrorRate*100)
+	if rep.Dropped > 0 {
+		fmt.Fprintf(w, "dropped %d scheduled requests: all workers were busy, raise -concurrency\n", rep.Dropped)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\trequests\trps\terrors\tp50 ms\tp90 ms\tp95 ms\tp99 ms\tmax ms\tstatuses\t")
+	for _, op := range rep.Operations {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%.2f%%\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%s\t\n",
+			op.Name, op.Requests, op.RPS, op.ErrorRate*100,
+			op.P50, op.P90, op.P95, op.P99, op.Max, statuses(op.Statuses))
+	}
+	return tw.Flush()
+}
+
+func encodeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func statuses(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var out string
+	for i, k := range keys {
+		if i > 0 {
+			out += " "
+		}
+		out += fmt.Sprintf("%s:%d", k, counts[k])
+	}
+	return out
+}
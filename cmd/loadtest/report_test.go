@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		name     string
+		p        float64
+		expected time.Duration
+	}{
+		{name: "Медиана", p: 50, expected: 50 * time.Millisecond},
+		{name: "p99", p: 99, expected: 99 * time.Millisecond},
+		{name: "Максимум", p: 100, expected: 100 * time.Millisecond},
+		{name: "Нулевой перцентиль - минимум", p: 0, expected: time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, percentile(sorted, tt.p))
+		})
+	}
+
+	assert.Zero(t, percentile(nil, 50))
+}
+
+func TestRecorder_Report(t *testing.T) {
+	rec := newRecorder()
+	rec.record("info", 10*time.Millisecond, 200, nil)
+	rec.record("info", 30*time.Millisecond, 200, nil)
+	rec.record("buy", 20*time.Millisecond, 400, nil)
+	rec.record("buy", 40*time.Millisecond, 0, errors.New("connection refused"))
+
+	rep := rec.report(2 * time.Second)
+
+	assert.Equal(t, 4, rep.Requests)
+	assert.Equal(t, 2, rep.Errors)
+	assert.Equal(t, 0.5, rep.ErrorRate)
+	assert.Equal(t, 2.0, rep.RPS)
+	require.Len(t, rep.Operations, 2)
+
+	buy := rep.Operations[0]
+	assert.Equal(t, "buy", buy.Name)
+	assert.Equal(t, 2, buy.Errors)
+	assert.Equal(t, map[string]int{"400": 1, "transport": 1}, buy.Statuses)
+	assert.Equal(t, 40.0, buy.Max)
+
+	info := rep.Operations[1]
+	assert.Equal(t, "info", info.Name)
+	assert.Zero(t, info.Errors)
+	assert.Equal(t, 10.0, info.P50)
+	assert.Equal(t, 30.0, info.P99)
+
+	var out bytes.Buffer
+	require.NoError(t, rep.WriteText(&out))
+	assert.Contains(t, out.String(), "errors 2 (50.00%)")
+	assert.Contains(t, out.String(), "400:1 transport:1")
+}
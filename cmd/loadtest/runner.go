@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	opInfo = "info"
+	opSend = "send"
+	opBuy  = "buy"
+)
+
+type weightedOp struct {
+	name   string
+	weight int
+}
+
+// mix - доли операций в нагрузке, например info=60,send=30,buy=10
+type mix []weightedOp
+
+func parseMix(s string) (mix, error) {
+	var m mix
+	total := 0
+	for _, part := range strings.Split(s, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected OP=WEIGHT, got %q", part)
+		}
+		switch name {
+		case opInfo, opSend, opBuy:
+		default:
+			return nil, fmt.Errorf("unknown operation %q, expected info, send or buy", name)
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("weight of %s must be a non-negative integer", name)
+		}
+		m = append(m, weightedOp{name: name, weight: w})
+		total += w
+	}
+	if total == 0 {
+		return nil, errors.New("at least one operation must have a positive weight")
+	}
+	return m, nil
+}
+
+func (m mix) pick() string {
+	total := 0
+	for _, op := range m {
+		total += op.weight
+	}
+	n := rand.IntN(total)
+	for _, op := range m {
+		if n < op.weight {
+			return op.name
+		}
+		n -= op.weight
+	}
+	return m[len(m)-1].name
+}
+
+type options struct {
+	users       int
+	password    string
+	prefix      string
+	duration    time.Duration
+	rate        float64
+	concurrency int
+	mix         mix
+	items       []string
+	amount      int
+	timeout     time.Duration
+}
+
+type user struct {
+	name  string
+	token string
+}
+
+// errRateLimited - сервис ограничил частоту входа, синтетических пользователей не создать
+var errRateLimited = errors.New("/api/auth answered 429: run the service with RATE_LIMIT_ANONYMOUS=off and RATE_LIMIT_USER=off")
+
+// setup создаёт пользователей через /api/auth: при первом входе пользователь регистрируется
+func setup(ctx context.Context, c *client, opts options, rec *recorder) ([]user, error) {
+	users := make([]user, opts.users)
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var setupErr error
+
+	for i := range users {
+		users[i].name = fmt.Sprintf("%s-%d", opts.prefix, i)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u *user) {
+			defer func() { <-sem; wg.Done() }()
+			reqCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+			defer cancel()
+
+			start := time.Now()
+			token, status, err := c.auth(reqCtx, u.name, opts.password)
+			rec.record("auth", time.Since(start), status, err)
+			switch {
+			case err != nil:
+				once.Do(func() { setupErr = fmt.Errorf("auth %s: %w", u.name, err) })
+			case status == http.StatusTooManyRequests:
+				once.Do(func() { setupErr = errRateLimited })
+			case status != http.StatusOK:
+				once.Do(func() { setupErr = fmt.Errorf("auth %s: unexpected status %d", u.name, status) })
+			}
+			u.token = token
+		}(&users[i])
+	}
+	wg.Wait()
+	return users, setupErr
+}
+
+// drive гоняет нагрузку opts.duration. При opts.rate > 0 запросы планируются с постоянной частотой,
+// и если все воркеры заняты, запланированный запрос пропускается и учитывается в dropped.
+// Иначе каждый воркер шлёт следующий запрос сразу после предыдущего.
+func drive(ctx context.Context, c *client, opts options, users []user, rec *recorder) (dropped int) {
+	runCtx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	var jobs chan struct{}
+	if opts.rate > 0 {
+		jobs = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if jobs != nil {
+					select {
+					case <-runCtx.Done():
+						return
+					case <-jobs:
+					}
+				} else if runCtx.Err() != nil {
+					return
+				}
+				// Запрос не привязан к runCtx: начатые запросы доводятся до конца, а не обрываются по сроку
+				execute(ctx, c, opts, users, rec)
+			}
+		}()
+	}
+
+	if jobs != nil {
+		dropped = schedule(runCtx, opts.rate, jobs)
+	}
+	wg.Wait()
+	return dropped
+}
+
+// schedule раздаёт воркерам запросы с частотой rate в секунду, пока не истечёт ctx
+func schedule(ctx context.Context, rate float64, jobs chan<- struct{}) (dropped int) {
+	interval := time.Duration(float64(time.Second) / rate)
+	start := time.Now()
+	for i := 0; ; i++ {
+		next := start.Add(time.Duration(i) * interval)
+		if wait := time.Until(next); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return dropped
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return dropped
+		}
+
+		select {
+		case jobs <- struct{}{}:
+		default:
+			dropped++
+		}
+	}
+}
+
+func execute(ctx context.Context, c *client, opts options, users []user, rec *recorder) {
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	i := rand.IntN(len(users))
+	from := users[i]
+	op := opts.mix.pick()
+	start := time.Now()
+	var status int
+	var err error
+	switch op {
+	case opInfo:
+		status, err = c.info(ctx, from.token)
+	case opSend:
+		// Получатель - любой другой пользователь: переводы по кругу не меняют общую сумму монет
+		j := rand.IntN(len(users) - 1)
+		if j >= i {
+			j++
+		}
+		status, err = c.sendCoin(ctx, from.token, users[j].name, opts.amount)
+	case opBuy:
+		status, err = c.buy(ctx, from.token, opts.items[rand.IntN(len(opts.items))])
+	}
+	rec.record(op, time.Since(start), status, err)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/service"
+)
+
+func TestParseMix(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    mix
+		expectedErr string
+	}{
+		{
+			name:     "Все операции",
+			input:    "info=60, send=30,buy=10",
+			expected: mix{{opInfo, 60}, {opSend, 30}, {opBuy, 10}},
+		},
+		{name: "Только одна операция", input: "buy=1", expected: mix{{opBuy, 1}}},
+		{name: "Неизвестная операция", input: "info=1,delete=1", expectedErr: `unknown operation "delete"`},
+		{name: "Без веса", input: "info", expectedErr: "expected OP=WEIGHT"},
+		{name: "Отрицательный вес", input: "info=-1", expectedErr: "non-negative integer"},
+		{name: "Все веса нулевые", input: "info=0,buy=0", expectedErr: "positive weight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := parseMix(tt.input)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
+func TestMix_PickSkipsZeroWeight(t *testing.T) {
+	m := mix{{opInfo, 0}, {opBuy, 1}}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, opBuy, m.pick())
+	}
+}
+
+// fakeAPI отвечает как сервис: токен равен имени пользователя, переводы себе запрещены
+type fakeAPI struct {
+	mu        sync.Mutex
+	users     map[string]bool
+	selfSends int
+	authCode  int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/auth" {
+		if f.authCode != 0 {
+			w.WriteHeader(f.authCode)
+			return
+		}
+		var req struct{ Username string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		f.users[req.Username] = true
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": req.Username})
+		return
+	}
+
+	from := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.users[from] {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.URL.Path == "/api/info":
+	case r.URL.Path == "/api/sendCoin":
+		var req struct{ ToUser string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.ToUser == from {
+			f.selfSends++
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	case strings.HasPrefix(r.URL.Path, "/api/buy/"):
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testOptions() options {
+	m, _ := parseMix("info=1,send=1,buy=1")
+	return options{
+		users:       5,
+		password:    "secret",
+		prefix:      "test",
+		duration:    200 * time.Millisecond,
+		concurrency: 4,
+		mix:         m,
+		items:       []string{"pen"},
+		amount:      1,
+		timeout:     time.Second,
+	}
+}
+
+func TestLoadtest(t *testing.T) {
+	api := &fakeAPI{users: make(map[string]bool)}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	c := &client{baseURL: srv.URL, http: srv.Client()}
+
+	res, err := loadtest(context.Background(), c, testOptions())
+
+	require.NoError(t, err)
+	assert.Len(t, api.users, 5)
+	assert.Equal(t, 5, res.Setup.Requests)
+	assert.Positive(t, res.Load.Requests)
+	assert.Zero(t, res.Load.Errors)
+	assert.Zero(t, api.selfSends)
+	var names []string
+	for _, op := range res.Load.Operations {
+		names = append(names, op.Name)
+	}
+	assert.Equal(t, []string{opBuy, opInfo, opSend}, names)
+}
+
+func TestLoadtest_Rate(t *testing.T) {
+	api := &fakeAPI{users: make(map[string]bool)}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	c := &client{baseURL: srv.URL, http: srv.Client()}
+	opts := testOptions()
+	opts.rate = 50
+	opts.duration = 500 * time.Millisecond
+
+	res, err := loadtest(context.Background(), c, opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, res.Load.TargetRPS)
+	// 50 rps за полсекунды - около 25 запросов, с запасом на медленную машину
+	assert.InDelta(t, 25, res.Load.Requests+res.Load.Dropped, 5)
+}
+
+func TestLoadtest_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{users: make(map[string]bool), authCode: http.StatusTooManyRequests})
+	defer srv.Close()
+	c := &client{baseURL: srv.URL, http: srv.Client()}
+
+	_, err := loadtest(context.Background(), c, testOptions())
+
+	assert.ErrorIs(t, err, errRateLimited)
+}
+
+func TestCheckThresholds(t *testing.T) {
+	tests := []struct {
+		name        string
+		report      Report
+		expectedErr string
+	}{
+		{name: "Всё в порядке", report: Report{ErrorRate: 0.005, RPS: 98, TargetRPS: 100}},
+		{name: "Много ошибок", report: Report{ErrorRate: 0.02}, expectedErr: "error rate 2.00% exceeds 1.00%"},
+		{name: "Частота не достигнута", report: Report{RPS: 80, TargetRPS: 100}, expectedErr: "achieved 80.0 rps of target 100.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkThresholds(tt.report, 0.01)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, errThreshold)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestDefaults_PassRegistration(t *testing.T) {
+	assert.Empty(t, service.CheckPasswordStrength(defaultPassword))
+	assert.Empty(t, service.CheckUsername(fmt.Sprintf("load-%d-%d", time.Now().Unix(), 999)))
+}
@@ -2,42 +2,163 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"github.com/joho/godotenv"
-	"log"
-	"merchio/internal/api/handler"
+	"errors"
+	"log/slog"
+	"merchio/internal/api/middleware"
 	"merchio/internal/api/router"
-	repo "merchio/internal/repository/user"
+	"merchio/internal/bootstrap"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/config"
+	"merchio/internal/health"
+	"merchio/internal/lifecycle"
+	"merchio/internal/logger"
+	"merchio/internal/metrics"
+	"merchio/internal/migrations"
+	"merchio/internal/ratelimit"
 	serv "merchio/internal/service/user"
+	"merchio/internal/tracing"
+	"net"
 	"net/http"
 	"os"
+	"time"
 )
 
-func Load(path string) error {
-	err := godotenv.Load(path)
+func main() {
+	ctx := context.Background()
+	cfg, args, err := config.Load(os.Args[1:], os.LookupEnv)
 	if err != nil {
-		return err
+		fatal("load config", err)
 	}
-	return nil
-}
+	// Уровень уже проверен в config.Load
+	level, _ := logger.ParseLevel(cfg.LogLevel)
+	slog.SetDefault(logger.New(os.Stdout, level))
+	slog.Info("config loaded", "config", cfg.String())
 
-func main() {
-	ctx := context.Background()
-	err := Load("/app/.env")
+	tracingOpts := tracing.Options{
+		ServiceName:  "merchio",
+		Exporter:     cfg.TracingExporter,
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		OTLPInsecure: cfg.TracingOTLPInsecure,
+	}
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
 	if err != nil {
-		log.Fatalf("Error loading .env file", err)
+		fatal("set up tracing", err)
 	}
-	pgDSN := os.Getenv("POSTGRES_CONN")
-	fmt.Println(pgDSN)
 
-	repository := repo.NewRepository(ctx, pgDSN)
-	service := serv.NewService(repository)
-	h := handler.NewImplementation(service)
-	r := router.NewRouter(h)
+	pgOpts := cfg.PostgresOptions()
+	if tracingOpts.Enabled() {
+		pgOpts.Configure = tracing.InstrumentPool
+	}
+	db, err := pg.Connect(ctx, cfg.PostgresDSN, pgOpts)
+	if err != nil {
+		fatal("connect to postgres", err)
+	}
+	pool := db.Pool()
 
-	err = http.ListenAndServe(":8080", r)
+	app := lifecycle.New()
+	// Трассировка останавливается последней, чтобы дослать спаны остановки
+	app.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: shutdownTracing,
+	})
+	// Пул добавляется первым, чтобы закрыться последним, после остановки HTTP-сервера
+	app.Append(lifecycle.Hook{
+		Name: "postgres",
+		OnStop: func(ctx context.Context) error {
+			db.Close()
+			return nil
+		},
+	})
+
+	loaded, err := migrations.Load(migrations.Files)
 	if err != nil {
-		log.Fatal(err)
+		fatal("load migrations", err)
 	}
+	migrator := migrations.NewMigrator(pool, loaded)
+
+	// ./main [flags] migrate status|up|down [n]|to <version>
+	if len(args) > 0 && args[0] == "migrate" {
+		err := runMigrate(ctx, migrator, args[1:])
+		db.Close()
+		if err != nil {
+			fatal("migrate", err)
+		}
+		return
+	}
+
+	if cfg.MigrateOnStart {
+		app.Append(lifecycle.Hook{
+			Name: "migrations",
+			OnStart: func(ctx context.Context) error {
+				_, err := migrator.Up(ctx)
+				return err
+			},
+		})
+	}
+
+	h := bootstrap.NewHandler(pool, bootstrap.Options{
+		Lockout: serv.LockoutPolicy{
+			MaxFailures: cfg.LoginMaxFailures,
+			Lockout:     cfg.LoginLockout,
+			MaxLockout:  cfg.LoginMaxLockout,
+		},
+		Hasher:        cfg.PasswordHasher(),
+		ResetTokenTTL: cfg.PasswordResetTTL,
+		Tx:            cfg.TxOptions(),
+	})
+	metrics.Registry.MustRegister(metrics.NewPoolCollector(pool))
+
+	probes := health.New(cfg.HealthCheckTimeout)
+	probes.Register("postgres", health.PingCheck(pool))
+	probes.Register("migrations", health.MigrationsCheck(migrator))
+	limiter := middleware.NewRateLimiter(ratelimit.NewMemoryStore(), cfg.RateLimitPolicy())
+	r := router.NewRouter(h, probes, limiter)
+
+	server := &http.Server{Addr: cfg.HTTPAddr, Handler: r}
+	app.Append(lifecycle.Hook{
+		Name: "http",
+		OnStart: func(ctx context.Context) error {
+			// Слушаем порт синхронно, чтобы занятый адрес был ошибкой запуска
+			ln, err := net.Listen("tcp", cfg.HTTPAddr)
+			if err != nil {
+				return err
+			}
+			slog.Info("http server listening", "addr", ln.Addr().String())
+			go func() {
+				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					app.Fail(err)
+				}
+			}()
+			return nil
+		},
+		// Shutdown перестаёт принимать соединения и ждёт текущие запросы до таймаута
+		OnStop: server.Shutdown,
+	})
+
+	// Добавлен после HTTP, поэтому при остановке readiness гаснет раньше, чем сервер перестаёт принимать запросы
+	app.Append(lifecycle.Hook{
+		Name: "readiness",
+		OnStop: func(ctx context.Context) error {
+			probes.SetShuttingDown()
+			// Пока балансировщик не заметил 503 на /readyz, он продолжает слать запросы - обслуживаем их
+			timer := time.NewTimer(cfg.ShutdownDrainDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	if err := app.Run(ctx, cfg.ShutdownTimeout); err != nil {
+		fatal("run", err)
+	}
+	slog.Info("stopped")
+}
 
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"merchio/internal/api/middleware"
+	"merchio/internal/api/openapi"
+	"merchio/internal/model"
+	"net/http"
+)
+
+// BuyItem покупает товар, вариант, купон и получатель подарка передаются в query:
+// /api/buy/hoody?variant=HOODY-GRY-M&coupon=SPRING20&recipient=bob
+func (i *Implementation) BuyItem(w http.ResponseWriter, r *http.Request, item string, params openapi.BuyItemParams) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	purchase, err := i.inventoryService.BuyItem(r.Context(), model.PurchaseRequest{
+		UserID:    uint(userID),
+		Item:      item,
+		SKU:       deref(params.Variant),
+		Coupon:    deref(params.Coupon),
+		Recipient: deref(params.Recipient),
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, purchase)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
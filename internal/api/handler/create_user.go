@@ -1,29 +1,40 @@
 package handler
 
 import (
-	"encoding/json"
+	"merchio/internal/api/middleware"
+	"merchio/internal/service"
 	"net/http"
 )
 
-func (i *Implementation) AuthHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+type authRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Validate проверяет только то, что нужно для входа: правила для имени и сложности пароля
+// применяются при регистрации в сервисе, иначе не смогли бы войти пользователи, созданные до них
+func (req *authRequest) Validate() error {
+	errs := service.FieldErrors{}
+	if req.Username == "" {
+		errs.Add("username", "is required")
 	}
+	errs.Add("password", service.CheckPasswordLength(req.Password))
+	return errs.Err()
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+func (i *Implementation) Auth(w http.ResponseWriter, r *http.Request) {
+	var req authRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	token, err := i.userService.Auth(r.Context(), req.Username, req.Password)
+	token, err := i.userService.Auth(r.Context(), req.Username, req.Password, middleware.ClientIP(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
+	writeJSON(w, http.StatusOK, map[string]string{
 		"token": token,
 	})
-
 }
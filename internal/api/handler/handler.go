@@ -1,13 +1,58 @@
 package handler
 
-import "merchio/internal/service"
+import (
+	"merchio/internal/api/openapi"
+	"merchio/internal/api/request"
+	"merchio/internal/api/response"
+	"merchio/internal/service"
+	"net/http"
+)
 
 type Implementation struct {
-	userService service.UserService
+	userService         service.UserService
+	transferService     service.TransferService
+	merchService        service.MerchService
+	wishlistService     service.WishlistService
+	notificationService service.NotificationService
+	inventoryService    service.InventoryService
+	orderService        service.OrderService
+	promotionService    service.PromotionService
 }
 
-func NewImplementation(userService service.UserService) *Implementation {
+// Операции из openapi.yaml реализуются методами с сигнатурами сгенерированного интерфейса
+var _ openapi.ServerInterface = (*Implementation)(nil)
+
+func NewImplementation(
+	userService service.UserService,
+	transferService service.TransferService,
+	merchService service.MerchService,
+	wishlistService service.WishlistService,
+	notificationService service.NotificationService,
+	inventoryService service.InventoryService,
+	orderService service.OrderService,
+	promotionService service.PromotionService,
+) *Implementation {
 	return &Implementation{
-		userService: userService,
+		userService:         userService,
+		transferService:     transferService,
+		merchService:        merchService,
+		wishlistService:     wishlistService,
+		notificationService: notificationService,
+		inventoryService:    inventoryService,
+		orderService:        orderService,
+		promotionService:    promotionService,
 	}
 }
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	response.JSON(w, status, v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	response.Error(w, r, err)
+}
+
+// decodeJSON - общий разбор тела для всех обработчиков, см. request.DecodeJSON
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	return request.DecodeJSON(w, r, dst)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"merchio/internal/api/middleware"
+	"net/http"
+)
+
+func (i *Implementation) GetInfo(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	info, err := i.inventoryService.GetInfo(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
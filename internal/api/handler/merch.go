@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"merchio/internal/model"
+	"net/http"
+)
+
+func (i *Implementation) ListMerchHandler(w http.ResponseWriter, r *http.Request) {
+	items, err := i.merchService.ListItems(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items": items,
+	})
+}
+
+func (i *Implementation) UpdateMerchHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Price int `json:"price"`
+		Stock int `json:"stock"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	item, err := i.merchService.UpdateItem(r.Context(), chi.URLParam(r, "item"), req.Price, req.Stock)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (i *Implementation) UpdateVariantHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Size          string `json:"size"`
+		Color         string `json:"color"`
+		PriceOverride *int   `json:"price_override"`
+		Stock         int    `json:"stock"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	variant, err := i.merchService.UpdateVariant(r.Context(), chi.URLParam(r, "item"), model.MerchVariant{
+		SKU:           chi.URLParam(r, "sku"),
+		Size:          req.Size,
+		Color:         req.Color,
+		PriceOverride: req.PriceOverride,
+		Stock:         req.Stock,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, variant)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"merchio/internal/api/middleware"
+	"merchio/internal/service"
+	"net/http"
+	"strconv"
+)
+
+func (i *Implementation) ListNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	notifications, err := i.notificationService.ListNotifications(r.Context(), userID, unreadOnly)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"notifications": notifications,
+	})
+}
+
+func (i *Implementation) MarkNotificationReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, r, service.Validation("invalid notification id"))
+		return
+	}
+
+	if err := i.notificationService.MarkRead(r.Context(), userID, uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"merchio/internal/api/middleware"
+	"merchio/internal/service"
+	"net/http"
+	"strconv"
+)
+
+func (i *Implementation) ListOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	orders, err := i.orderService.ListOrders(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"orders": orders,
+	})
+}
+
+func (i *Implementation) ListAllOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	orders, err := i.orderService.ListAllOrders(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"orders": orders,
+	})
+}
+
+func (i *Implementation) AdvanceOrderHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PickupLocation string `json:"pickup_location"`
+	}
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, r, service.Validation("invalid order id"))
+		return
+	}
+	// Тело необязательное, место выдачи нужно только при переходе в ready_for_pickup
+	if r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	line, err := i.orderService.AdvanceOrder(r.Context(), uint(id), req.PickupLocation)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, line)
+}
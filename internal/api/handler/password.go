@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"merchio/internal/api/middleware"
+	"merchio/internal/service"
+	"net/http"
+)
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// Validate проверяет только формат, сложность нового пароля проверяет сервис
+func (req *changePasswordRequest) Validate() error {
+	errs := service.FieldErrors{}
+	errs.Add("currentPassword", service.CheckPasswordLength(req.CurrentPassword))
+	errs.Add("newPassword", service.CheckPasswordLength(req.NewPassword))
+	return errs.Err()
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (req *resetPasswordRequest) Validate() error {
+	errs := service.FieldErrors{}
+	if req.Token == "" {
+		errs.Add("token", "is required")
+	}
+	errs.Add("newPassword", service.CheckPasswordLength(req.NewPassword))
+	return errs.Err()
+}
+
+func (i *Implementation) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	var req changePasswordRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := i.userService.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// IssueResetTokenHandler выдаёт администратору одноразовый токен, который он передаёт пользователю
+func (i *Implementation) IssueResetTokenHandler(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	token, err := i.userService.IssueResetToken(r.Context(), adminID, chi.URLParam(r, "username"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, token)
+}
+
+func (i *Implementation) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := i.userService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"merchio/internal/model"
+	"net/http"
+	"time"
+)
+
+func (i *Implementation) CreatePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name     string    `json:"name"`
+		Item     string    `json:"item"`
+		Category string    `json:"category"`
+		Kind     string    `json:"kind"`
+		Value    int       `json:"value"`
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	promotion, err := i.promotionService.CreatePromotion(r.Context(), req.Item, model.Promotion{
+		Name:     req.Name,
+		Category: req.Category,
+		Discount: model.Discount{Kind: req.Kind, Value: req.Value},
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, promotion)
+}
+
+func (i *Implementation) ListPromotionsHandler(w http.ResponseWriter, r *http.Request) {
+	promotions, err := i.promotionService.ListPromotions(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"promotions": promotions,
+	})
+}
+
+func (i *Implementation) CreateCouponHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code     string     `json:"code"`
+		Item     string     `json:"item"`
+		Category string     `json:"category"`
+		Kind     string     `json:"kind"`
+		Value    int        `json:"value"`
+		MaxUses  *int       `json:"max_uses"`
+		StartsAt *time.Time `json:"starts_at"`
+		EndsAt   *time.Time `json:"ends_at"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	coupon, err := i.promotionService.CreateCoupon(r.Context(), req.Item, model.Coupon{
+		Code:     req.Code,
+		Category: req.Category,
+		Discount: model.Discount{Kind: req.Kind, Value: req.Value},
+		MaxUses:  req.MaxUses,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, coupon)
+}
+
+func (i *Implementation) ListCouponsHandler(w http.ResponseWriter, r *http.Request) {
+	coupons, err := i.promotionService.ListCoupons(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"coupons": coupons,
+	})
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"merchio/internal/api/middleware"
+	"merchio/internal/service"
+	"net/http"
+)
+
+type sendCoinRequest struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+}
+
+func (req *sendCoinRequest) Validate() error {
+	errs := service.FieldErrors{}
+	if req.ToUser == "" {
+		errs.Add("toUser", "is required")
+	}
+	if req.Amount <= 0 {
+		errs.Add("amount", "must be positive")
+	}
+	return errs.Err()
+}
+
+func (i *Implementation) SendCoin(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	var req sendCoinRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := i.transferService.SendCoins(r.Context(), userID, req.ToUser, req.Amount); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
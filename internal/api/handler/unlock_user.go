@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"merchio/internal/api/middleware"
+	"net/http"
+)
+
+// UnlockUserHandler снимает блокировку входа после серии неудачных попыток
+func (i *Implementation) UnlockUserHandler(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	cleared, err := i.userService.UnlockUser(r.Context(), adminID, chi.URLParam(r, "username"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int64{
+		"cleared": cleared,
+	})
+}
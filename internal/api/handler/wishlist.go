@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"merchio/internal/api/middleware"
+	"net/http"
+)
+
+func (i *Implementation) GetWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	wishlist, err := i.wishlistService.GetWishlist(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, wishlist)
+}
+
+func (i *Implementation) AddWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	if err := i.wishlistService.AddItem(r.Context(), userID, chi.URLParam(r, "item")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (i *Implementation) RemoveWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	if err := i.wishlistService.RemoveItem(r.Context(), userID, chi.URLParam(r, "item")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,32 +2,56 @@ package middleware
 
 import (
 	"context"
+	"log/slog"
+	"merchio/internal/api/response"
+	"merchio/internal/logger"
+	"merchio/internal/model"
 	"merchio/internal/utils"
 	"net/http"
 	"strings"
 )
 
+// verifyToken подменяется в тестах
+var verifyToken = utils.VerifyToken
+
 func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		tokenString := r.Header.Get("Authorization")
 		if tokenString == "" {
 			// тут должен быть редирект наверно
-			http.Error(w, "отсутствует токен", http.StatusUnauthorized)
+			response.Message(w, http.StatusUnauthorized, "отсутствует токен")
 			return
 		}
 
 		// Удаляем префикс "Bearer " если он есть
 		tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
 
-		claims, err := utils.VerifyToken(tokenString)
+		claims, err := verifyToken(tokenString)
 		if err != nil {
-			http.Error(w, "недействительный токен", http.StatusUnauthorized)
+			response.Message(w, http.StatusUnauthorized, "недействительный токен")
 			return
 		}
 
 		// Добавляем данные пользователя в контекст
 		ctx := context.WithValue(r.Context(), "user_id", claims.UserId)
-		//ctx = context.WithValue(ctx, "role", claims.Role)
+		ctx = context.WithValue(ctx, "role", claims.Role)
+		ctx = logger.WithAttrs(ctx, slog.Int("user_id", claims.UserId))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
+
+// AdminMiddleware пропускает только пользователей с ролью администратора
+func AdminMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if role, _ := r.Context().Value("role").(string); role != model.RoleAdmin {
+			response.Message(w, http.StatusForbidden, "недостаточно прав")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value("user_id").(int)
+	return userID, ok
+}
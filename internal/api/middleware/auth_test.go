@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"errors"
-	"github.com/dgrijalva/jwt-go"
+	"merchio/internal/utils"
 	"net/http"
 	"net/http/httptest"
 
@@ -11,20 +11,20 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-type Claims struct {
-	jwt.StandardClaims
-	UserId int `json:"user_id"`
-}
-
 // Мок для utils.VerifyToken
-func mockVerifyToken(token string) (*Claims, error) {
+func mockVerifyToken(token string) (*utils.UserClaims, error) {
 	if token == "validtoken" {
-		return &Claims{UserId: 12345}, nil
+		return &utils.UserClaims{UserId: 12345, Role: "user"}, nil
+	}
+	if token == "admintoken" {
+		return &utils.UserClaims{UserId: 1, Role: "admin"}, nil
 	}
 	return nil, errors.New("invalid token")
 }
 
 func TestAuthMiddleware(t *testing.T) {
+	verifyToken = mockVerifyToken
+	defer func() { verifyToken = utils.VerifyToken }()
 
 	tests := []struct {
 		name           string
@@ -56,8 +56,9 @@ func TestAuthMiddleware(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Хендлер для проверки контекста
 			handler := AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
-				userID := r.Context().Value("user_id")
-				assert.Equal(t, "12345", userID)
+				userID, ok := UserIDFromContext(r.Context())
+				assert.True(t, ok)
+				assert.Equal(t, 12345, userID)
 				w.Write([]byte("ok"))
 			})
 
@@ -74,3 +75,48 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminMiddleware(t *testing.T) {
+	verifyToken = mockVerifyToken
+	defer func() { verifyToken = utils.VerifyToken }()
+
+	tests := []struct {
+		name           string
+		authHeader     string
+		expectedStatus int
+	}{
+		{
+			name:           "No token provided",
+			authHeader:     "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Regular user",
+			authHeader:     "Bearer validtoken",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin",
+			authHeader:     "Bearer admintoken",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AdminMiddleware(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("ok"))
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
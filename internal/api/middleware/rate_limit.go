@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"merchio/internal/api/response"
+	"merchio/internal/ratelimit"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RateLimiter ограничивает частоту запросов к маршруту: авторизованных - по user_id,
+// остальных - по IP. Для авторизованных маршрутов Limit ставится внутри AuthMiddleware.
+type RateLimiter struct {
+	store  ratelimit.Store
+	policy ratelimit.Policy
+}
+
+func NewRateLimiter(store ratelimit.Store, policy ratelimit.Policy) *RateLimiter {
+	return &RateLimiter{store: store, policy: policy}
+}
+
+// Limit отвечает 429 с Retry-After, когда корзина клиента пуста. С nil-лимитером пропускает всё.
+func (l *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	if l == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Обработчик вызывается после маршрутизации, поэтому шаблон маршрута уже известен
+		route := r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+
+		var key string
+		userID, authenticated := UserIDFromContext(r.Context())
+		if authenticated {
+			key = route + "|user:" + strconv.Itoa(userID)
+		} else {
+			key = route + "|ip:" + ClientIP(r)
+		}
+
+		result, err := l.store.Take(r.Context(), key, l.policy.For(route, authenticated))
+		if err != nil {
+			// Недоступное общее хранилище не должно останавливать API
+			slog.WarnContext(r.Context(), "rate limit store failed, request allowed", "error", err)
+			next(w, r)
+			return
+		}
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(result.RetryAfter.Seconds())))))
+			response.Message(w, http.StatusTooManyRequests, "too many requests")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ClientIP - адрес соединения без порта. Заголовкам X-Forwarded-For не доверяем: их подделывает клиент.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis: connection refused")
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), ratelimit.Policy{
+		Anonymous: ratelimit.Every(2, time.Minute),
+		User:      ratelimit.Every(1, time.Minute),
+	})
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := chi.NewRouter()
+	r.Post("/api/auth", limiter.Limit(ok))
+	r.Get("/api/info", func(w http.ResponseWriter, r *http.Request) {
+		// Вместо AuthMiddleware кладём пользователя из заголовка
+		ctx := context.WithValue(r.Context(), "user_id", len(r.Header.Get("X-User")))
+		limiter.Limit(ok)(w, r.WithContext(ctx))
+	})
+
+	send := func(method, path, ip, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = ip + ":40000"
+		req.Header.Set("X-User", user)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Анонимные запросы считаются по IP
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/auth", "10.0.0.1", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/auth", "10.0.0.1", "").Code)
+	rr := send(http.MethodPost, "/api/auth", "10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"errors":"too many requests"}`, rr.Body.String())
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/auth", "10.0.0.2", "").Code)
+
+	// Авторизованные - по user_id, независимо от IP
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/info", "10.0.0.1", "a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodGet, "/api/info", "10.0.0.9", "a").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/info", "10.0.0.1", "bb").Code)
+}
+
+func TestRateLimiter_StoreFailureAllows(t *testing.T) {
+	limiter := NewRateLimiter(failingStore{}, ratelimit.Policy{Anonymous: ratelimit.Every(1, time.Hour)})
+	r := chi.NewRouter()
+	r.Post("/api/auth", limiter.Limit(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/auth", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRateLimiter_Nil(t *testing.T) {
+	var limiter *RateLimiter
+	called := false
+	limiter.Limit(func(w http.ResponseWriter, r *http.Request) { called = true })(nil, nil)
+
+	assert.True(t, called)
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"merchio/internal/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		expectSame bool
+	}{
+		{
+			name:       "ID из заголовка сохраняется",
+			header:     "client-req_42.1",
+			expectSame: true,
+		},
+		{
+			name:   "Без заголовка генерируется новый",
+			header: "",
+		},
+		{
+			name:   "Недопустимые символы заменяются",
+			header: "bad id\nwith newline",
+		},
+		{
+			name:   "Слишком длинный заменяется",
+			header: strings.Repeat("a", 65),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logger.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.NotEmpty(t, seen)
+			assert.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+			if tt.expectSame {
+				assert.Equal(t, tt.header, seen)
+			} else {
+				assert.NotEqual(t, tt.header, seen)
+				assert.Len(t, seen, 32)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logger.New(&buf, slog.LevelInfo))
+	defer slog.SetDefault(prev)
+
+	r := chi.NewRouter()
+	r.Use(RequestID, AccessLog)
+	r.Get("/api/buy/{item}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "item is out of stock", http.StatusConflict)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/hoody", nil)
+	req.Header.Set(RequestIDHeader, "trace-me")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "trace-me", entry["request_id"])
+	assert.Equal(t, "/api/buy/{item}", entry["route"])
+	assert.Equal(t, "/api/buy/hoody", entry["path"])
+	assert.Equal(t, 409.0, entry["status"])
+}
@@ -0,0 +1,529 @@
+// Package openapi provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// AuthRequest defines model for AuthRequest.
+type AuthRequest struct {
+	Password string `json:"password"`
+
+	// Username При регистрации - от 3 до 32 символов из латиницы, цифр, '_', '-' и '.'; при входе не проверяется.
+	Username string `json:"username"`
+}
+
+// AuthResponse defines model for AuthResponse.
+type AuthResponse struct {
+	Token string `json:"token"`
+}
+
+// CoinHistory defines model for CoinHistory.
+type CoinHistory struct {
+	Received []struct {
+		Amount   int    `json:"amount"`
+		FromUser string `json:"fromUser"`
+	} `json:"received"`
+	Sent []struct {
+		Amount int    `json:"amount"`
+		ToUser string `json:"toUser"`
+	} `json:"sent"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Errors string `json:"errors"`
+
+	// Fields Ошибки по полям запроса.
+	Fields *map[string]string `json:"fields,omitempty"`
+}
+
+// InfoResponse defines model for InfoResponse.
+type InfoResponse struct {
+	CoinHistory CoinHistory     `json:"coinHistory"`
+	Coins       int             `json:"coins"`
+	Inventory   []InventoryItem `json:"inventory"`
+}
+
+// InventoryItem defines model for InventoryItem.
+type InventoryItem struct {
+	Color    *string `json:"color,omitempty"`
+	Quantity int     `json:"quantity"`
+	Size     *string `json:"size,omitempty"`
+	Sku      *string `json:"sku,omitempty"`
+	Type     string  `json:"type"`
+}
+
+// Purchase defines model for Purchase.
+type Purchase struct {
+	Balance     int     `json:"balance"`
+	Coupon      *string `json:"coupon,omitempty"`
+	Discount    int     `json:"discount"`
+	Item        string  `json:"item"`
+	ListPrice   int     `json:"list_price"`
+	OrderId     int     `json:"order_id"`
+	Price       int     `json:"price"`
+	PromotionId *int    `json:"promotion_id,omitempty"`
+	Recipient   *string `json:"recipient,omitempty"`
+	RecipientId *int    `json:"recipient_id,omitempty"`
+	Sku         *string `json:"sku,omitempty"`
+	UserId      int     `json:"user_id"`
+}
+
+// SendCoinRequest defines model for SendCoinRequest.
+type SendCoinRequest struct {
+	Amount int    `json:"amount"`
+	ToUser string `json:"toUser"`
+}
+
+// BadRequest defines model for BadRequest.
+type BadRequest = ErrorResponse
+
+// Conflict defines model for Conflict.
+type Conflict = ErrorResponse
+
+// InternalError defines model for InternalError.
+type InternalError = ErrorResponse
+
+// NotFound defines model for NotFound.
+type NotFound = ErrorResponse
+
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = ErrorResponse
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ErrorResponse
+
+// BuyItemParams defines parameters for BuyItem.
+type BuyItemParams struct {
+	// Variant SKU варианта товара.
+	Variant *string `form:"variant,omitempty" json:"variant,omitempty"`
+	Coupon  *string `form:"coupon,omitempty" json:"coupon,omitempty"`
+
+	// Recipient Имя получателя подарка.
+	Recipient *string `form:"recipient,omitempty" json:"recipient,omitempty"`
+}
+
+// AuthJSONRequestBody defines body for Auth for application/json ContentType.
+type AuthJSONRequestBody = AuthRequest
+
+// SendCoinJSONRequestBody defines body for SendCoin for application/json ContentType.
+type SendCoinJSONRequestBody = SendCoinRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Вход по логину и паролю, при первом входе пользователь создаётся.
+	// (POST /api/auth)
+	Auth(w http.ResponseWriter, r *http.Request)
+	// Покупка товара, в том числе варианта, по купону или в подарок.
+	// (GET /api/buy/{item})
+	BuyItem(w http.ResponseWriter, r *http.Request, item string, params BuyItemParams)
+	// Баланс, купленные товары и история переводов.
+	// (GET /api/info)
+	GetInfo(w http.ResponseWriter, r *http.Request)
+	// Перевод монет другому пользователю.
+	// (POST /api/sendCoin)
+	SendCoin(w http.ResponseWriter, r *http.Request)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// Вход по логину и паролю, при первом входе пользователь создаётся.
+// (POST /api/auth)
+func (_ Unimplemented) Auth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Покупка товара, в том числе варианта, по купону или в подарок.
+// (GET /api/buy/{item})
+func (_ Unimplemented) BuyItem(w http.ResponseWriter, r *http.Request, item string, params BuyItemParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Баланс, купленные товары и история переводов.
+// (GET /api/info)
+func (_ Unimplemented) GetInfo(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Перевод монет другому пользователю.
+// (POST /api/sendCoin)
+func (_ Unimplemented) SendCoin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// Auth operation middleware
+func (siw *ServerInterfaceWrapper) Auth(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Auth(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// BuyItem operation middleware
+func (siw *ServerInterfaceWrapper) BuyItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "item" -------------
+	var item string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "item", chi.URLParam(r, "item"), &item, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "item", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BuyItemParams
+
+	// ------------- Optional query parameter "variant" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "variant", r.URL.Query(), &params.Variant)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "variant", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "coupon" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "coupon", r.URL.Query(), &params.Coupon)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "coupon", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "recipient" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "recipient", r.URL.Query(), &params.Recipient)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "recipient", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.BuyItem(w, r, item, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetInfo operation middleware
+func (siw *ServerInterfaceWrapper) GetInfo(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetInfo(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SendCoin operation middleware
+func (siw *ServerInterfaceWrapper) SendCoin(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SendCoin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/auth", wrapper.Auth)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/buy/{item}", wrapper.BuyItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/info", wrapper.GetInfo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/sendCoin", wrapper.SendCoin)
+	})
+
+	return r
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/9RY224TTRJ+lVHvSrmZ2IawEuu9ArTshj0IcRAXUYSGcTtu8ExPunuyMpGlxAGCFEQE",
+	"V1zsQdon8JpYGZLYeYXqN1pV93g8dmaSgCD8/00Ud0911+Grqq96k/g8iHhIQyVJfZMIKiMeSmp+3PYa",
+	"D+h6TKXCXz4PFQ3Nv14UtZnvKcbD6nPJQ1yTfosGHv73W0GbpE5+U50eXbW7svpHIbh4kF5Cut2uSxpU",
+	"+oJFeBipE/i3fgsJ/A+OoO/AAPpwDAkcQF+/gQQSBxJccGAEQ/0a93XPfnoCY7PYq5CuS+7wsNlm/lUq",
+	"/l8YG322HDiEPhyhPnoX9dXbej9T/EjvwCnuOZDobb0LQ70Fp9CHkVF8OVRUhF7b3HeF2n+Ekd7RPb0F",
+	"QxjBSO+jyuNpLFwHDtC7NiCO7sEYjvU7NNOBgQPHMIbP0NevjRV/5+ouj8PGz3F/6unTVMXDdKcHQ/xt",
+	"sIN/+vAFbUod/4jzv3lhJwW8vELN/2OcPtB7+i1q4xjEn0CiexZJp3oLxnobrXCtVQN0v97CT/Q7J8XQ",
+	"EA6dB1SJzuKtpqLC0dswNGgbwUGFuKRFvQYVxrDcZ7OGqE5ESZ2wUNE1KlDbrkseh16sWlywl/QqA/ov",
+	"hJsF2pEBZd/N/TJRNOH7ord1DwaQ2ADbzWmVgIHxzkjvwRf0Xh+9iTioELw01QfVvRWrVq7eeY0GQ128",
+	"9n3BIyoUo5LUm15bUpdEuaVNEnlS/oML452AhX+l4Zpqkfo1d+JQqQQL1xBmscT0Dih+WoCDxDGR/Gxq",
+	"A2bjpO4tYjb2nCVMw7GzdB3DiygZoDEICbT5ELGDQE9gBIl+o/dcB+X1K73lOgtPF1xnYXHBgcRZqCz8",
+	"wTHIShwY6NcwRl+mqWEAZ/2m9zEKWMAQQufa1nWJoOsxE4iSlamh7tQ9q5kQf/ac+godYr2e4qC+OedZ",
+	"xV/QMIfMksvsZ0XH3+Es/DOTiovO2dMF9SnbsKhmigby7CdewGOL9fnccElT8OCxpCK3W6Jf9qU7ObBI",
+	"13TBE8Lr4G+ZZtk3qKb45RRT/GvVmjsgc2GqbtEBs7l+xg6K27JAV5c0GW03ZHk2FoiU0gnbESZtYR9O",
+	"ZqtrHwE+p/qcramiRSYuh01ebqE/C8Lz6mIer13XSMriELNwg4aTIzOInHf28kRiWdHgwsjaq/P3uDOG",
+	"FLshf0OBH9pcFEZtPfZCxVSn2FTJXtJCMfkiLly3CxeCH3dzdxdZdD8WfssrCuozr+2FPi3W2OdxxMNi",
+	"gDLplycuSx13RqzNpHoaCVZ2IxcNKp6yRvHuOYKR4AHHdCkVFtRnEaOhKlQs2y2VL4sStogSoblIZcZN",
+	"hVJXzTgm59yJyW4Wp6LoPqRhAzPu29r+tAAHLGRBHOTbYmEx/poGenFpNi3CjwVTnYeY4ikuqSeowLY6",
+	"/XWXi8BTpE7uPXlEUtKDJ9ndaeFrKRVZMsbCJi/gKP+EPtJ8OESKgUMXcoRdnL8O7bw2M0VAAsPcZKb3",
+	"TI1lqo13BVT4LcaJSzaokPb8a5VapWbQHNHQixipk6VKrbJkSIRqGfuqXsSqXmpexG3UMCyGhi43SN1w",
+	"CmK9SaW6zRud78Zb8ySxOxsyJWJqFnIj9PVa7TtfXc6Y7z15tDglyGaouVGrlZ2aqVnNjflG5NrFIjMj",
+	"AQpd//3FQvMTVtclv7uMfrMjcR71pL6y6hIZB4GHbZDAR8tk005vZ9IEMelAkif/792M/J6a8QBp9MkM",
+	"Ey6bHs0gdmheJD5MmDHqZGD5LO5UN7EuddGsNVqAzduxbY6IaOEFVJmZbGWTMIwhopy4xE4Ikwo3CzH3",
+	"7Lw2rSDzCfvwL48dOxZDgm8MOMDbKcouGtZjbl6PqehMr97wBPNMHT33tiLRtPd9lZ7wCU70/sTrO3o3",
+	"83i6eGDUPSpXeNqkzrt49QfmZ0YVCod8GKdPP+a1aqxfmQn+xA6saNZVpmvtxsVC2RuOEbhEfmdvbj+h",
+	"IExLwKyj81B38aHKLJzgm0mit9H5Z9LDTatH9lCnd7L3hEEejGM4yqX+pGEWJv2fqMIJgfxA9M1MIEUI",
+	"/ASjDHTmVcG88JUUOhhWfjXNYBr7D+Z1EuO47U4CeJw+aO7BMIcGvWc6gnllSZ/S9ie9YGi6wQF+mouv",
+	"TKliOeuYkMkfxDzmuerl2ccZDpfxsbzJ9jVthCTtF12KfmJlyaEjR2sdONBbegc+Y2XROyUZpd9XSHeO",
+	"vMyS9ZVVbE+Sio0JJYhFOyXl9Wq1zX2v3eJS1W/WbtZId7X7/wEArc5w4r0ZAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
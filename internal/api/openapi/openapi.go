@@ -0,0 +1,31 @@
+// Package openapi - контракт API: спецификация openapi.yaml и сгенерированные по ней типы и интерфейс сервера.
+// После правки спецификации: go generate ./internal/api/openapi
+package openapi
+
+import (
+	"encoding/json"
+	"merchio/internal/api/response"
+	"net/http"
+	"sync"
+)
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config oapi-codegen.yaml openapi.yaml
+
+var specJSON = sync.OnceValues(func() ([]byte, error) {
+	spec, err := GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(spec)
+})
+
+// SpecHandler отдаёт спецификацию в JSON, вшитую в бинарник при генерации
+func SpecHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := specJSON()
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
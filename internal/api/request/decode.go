@@ -0,0 +1,77 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"merchio/internal/service"
+	"net/http"
+	"strings"
+)
+
+// MaxBodyBytes - предел тела JSON-запроса
+const MaxBodyBytes = 1 << 20
+
+// Validator реализуют тела запросов, которым нужна проверка после разбора
+type Validator interface {
+	Validate() error
+}
+
+// DecodeJSON разбирает тело в dst: не больше MaxBodyBytes, без неизвестных полей, ровно один объект.
+// Если dst реализует Validator, вызывает Validate. Все ошибки - service.Error или service.FieldErrors.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return service.Validation("request body must contain a single JSON object")
+	}
+
+	if v, ok := dst.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// decodeError переводит ошибки encoding/json в понятный клиенту текст без деталей реализации
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return service.Validation("request body must not be empty")
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &syntaxErr):
+		return service.Validation("request body is not valid JSON")
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return service.FieldErrors{typeErr.Field: "must be " + jsonType(typeErr.Type.Kind().String())}
+		}
+		return service.Validation("request body must be a JSON object")
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return service.FieldErrors{field: "unknown field"}
+	case errors.As(err, &maxErr):
+		return service.Validation(fmt.Sprintf("request body must not exceed %d bytes", MaxBodyBytes))
+	default:
+		return service.Validation("invalid request body")
+	}
+}
+
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "a number"
+	case kind == "string":
+		return "a string"
+	case kind == "bool":
+		return "a boolean"
+	default:
+		return "a " + kind
+	}
+}
@@ -0,0 +1,95 @@
+package request
+
+import (
+	"errors"
+	"merchio/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type transferBody struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+}
+
+func (b *transferBody) Validate() error {
+	if b.Amount <= 0 {
+		return service.FieldErrors{"amount": "must be positive"}
+	}
+	return nil
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedFields service.FieldErrors
+		expectedError  string
+	}{
+		{
+			name: "Корректное тело",
+			body: `{"toUser":"bob","amount":10}`,
+		},
+		{
+			name:          "Пустое тело",
+			body:          ``,
+			expectedError: "request body must not be empty",
+		},
+		{
+			name:          "Сломанный JSON",
+			body:          `{"toUser":"bob",`,
+			expectedError: "request body is not valid JSON",
+		},
+		{
+			name:           "Неизвестное поле",
+			body:           `{"toUser":"bob","amount":10,"admin":true}`,
+			expectedFields: service.FieldErrors{"admin": "unknown field"},
+		},
+		{
+			name:           "Неверный тип поля",
+			body:           `{"toUser":"bob","amount":"10"}`,
+			expectedFields: service.FieldErrors{"amount": "must be a number"},
+		},
+		{
+			name:          "Лишние данные после объекта",
+			body:          `{"toUser":"bob","amount":10}{}`,
+			expectedError: "request body must contain a single JSON object",
+		},
+		{
+			name:          "Слишком большое тело",
+			body:          `{"toUser":"` + strings.Repeat("a", MaxBodyBytes) + `"}`,
+			expectedError: "request body must not exceed 1048576 bytes",
+		},
+		{
+			name:           "Вызывается Validate",
+			body:           `{"toUser":"bob","amount":0}`,
+			expectedFields: service.FieldErrors{"amount": "must be positive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(tt.body))
+			var dst transferBody
+
+			err := DecodeJSON(httptest.NewRecorder(), req, &dst)
+
+			switch {
+			case tt.expectedFields != nil:
+				var fields service.FieldErrors
+				assert.True(t, errors.As(err, &fields))
+				assert.Equal(t, tt.expectedFields, fields)
+			case tt.expectedError != "":
+				assert.EqualError(t, err, tt.expectedError)
+				assert.Equal(t, service.KindValidation, service.KindOf(err))
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, transferBody{ToUser: "bob", Amount: 10}, dst)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"merchio/internal/service"
+	"net/http"
+)
+
+// errorBody - единый формат ошибки API
+type errorBody struct {
+	Errors string            `json:"errors"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+func JSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Message отвечает ошибкой с заданным кодом и текстом
+func Message(w http.ResponseWriter, status int, message string) {
+	JSON(w, status, errorBody{Errors: message})
+}
+
+// Error переводит ошибку в код ответа. Клиент видит только текст доменной ошибки без обёрток,
+// остальные ошибки логируются, а клиент получает "internal server error".
+// Ошибки полей (service.FieldErrors) отдаются с кодом 400 и картой fields.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	var fields service.FieldErrors
+	if errors.As(err, &fields) {
+		JSON(w, http.StatusBadRequest, errorBody{Errors: fields.Error(), Fields: fields})
+		return
+	}
+
+	var domainErr *service.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == service.KindInternal {
+		slog.ErrorContext(r.Context(), "request failed", "error", err)
+		Message(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	Message(w, Status(domainErr.Kind), domainErr.Message)
+}
+
+func Status(kind service.Kind) int {
+	switch kind {
+	case service.KindValidation, service.KindInsufficientFunds:
+		return http.StatusBadRequest
+	case service.KindUnauthorized:
+		return http.StatusUnauthorized
+	case service.KindForbidden:
+		return http.StatusForbidden
+	case service.KindNotFound:
+		return http.StatusNotFound
+	case service.KindConflict:
+		return http.StatusConflict
+	case service.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
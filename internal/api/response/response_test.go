@@ -0,0 +1,93 @@
+package response
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"merchio/internal/logger"
+	"merchio/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Ошибка валидации",
+			err:            service.ErrInvalidAmount,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"amount must be positive"}`,
+		},
+		{
+			name:           "Недостаточно монет",
+			err:            service.ErrInsufficientCoins,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"insufficient coins"}`,
+		},
+		{
+			name:           "Неверный пароль",
+			err:            service.ErrInvalidCredentials,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"invalid username or password"}`,
+		},
+		{
+			name:           "Не найдено, ошибка обёрнута",
+			err:            fmt.Errorf("buy: %w", service.ErrItemNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"errors":"merch item not found"}`,
+		},
+		{
+			name:           "Конфликт",
+			err:            service.ErrOutOfStock,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"errors":"item is out of stock"}`,
+		},
+		{
+			name:           "Ошибки полей",
+			err:            service.FieldErrors{"username": "is required", "amount": "must be positive"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"amount: must be positive; username: is required","fields":{"amount":"must be positive","username":"is required"}}`,
+		},
+		{
+			name:           "Внутренняя ошибка не раскрывается",
+			err:            errors.New(`ERROR: relation "users" does not exist (SQLSTATE 42P01)`),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"errors":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			Error(rr, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestError_LogsInternalDetails(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logger.New(&buf, slog.LevelInfo))
+	defer slog.SetDefault(prev)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(logger.WithRequestID(req.Context(), "req-7"))
+	Error(httptest.NewRecorder(), req, errors.New("connection reset by peer"))
+
+	assert.Contains(t, buf.String(), "connection reset by peer")
+	assert.Contains(t, buf.String(), `"request_id":"req-7"`)
+}
@@ -3,12 +3,59 @@ package router
 import (
 	"github.com/go-chi/chi/v5"
 	"merchio/internal/api/handler"
+	"merchio/internal/api/middleware"
+	"merchio/internal/api/openapi"
+	"merchio/internal/api/response"
+	"merchio/internal/health"
+	"merchio/internal/metrics"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"net/http"
 )
 
-func NewRouter(userHandler *handler.Implementation) *chi.Mux {
+// NewRouter собирает маршруты. limiter может быть nil, тогда частота запросов не ограничивается.
+func NewRouter(userHandler *handler.Implementation, probes *health.Health, limiter *middleware.RateLimiter) *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID, tracing.Middleware, middleware.AccessLog, metrics.Middleware)
 
-	r.Post("/api/auth", userHandler.AuthHandler)
-	//	r.Get("/api/sendCoin", middleware.AuthMiddleware(userHandler.SendCoinHandler))  //Unimplemented
+	r.Get("/healthz", probes.LivenessHandler)
+	r.Get("/readyz", probes.ReadinessHandler)
+	r.Get("/metrics", metrics.Handler().ServeHTTP)
+
+	// Операции из спецификации идут через сгенерированную обёртку: она разбирает параметры пути и query
+	api := &openapi.ServerInterfaceWrapper{
+		Handler: userHandler,
+		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			response.Error(w, r, service.Validation(err.Error()))
+		},
+	}
+	r.Get("/api/openapi.json", openapi.SpecHandler)
+	r.Post("/api/auth", limiter.Limit(api.Auth))
+	r.Post("/api/sendCoin", middleware.AuthMiddleware(limiter.Limit(api.SendCoin)))
+	r.Post("/api/password/change", middleware.AuthMiddleware(limiter.Limit(userHandler.ChangePasswordHandler)))
+	r.Post("/api/password/reset", limiter.Limit(userHandler.ResetPasswordHandler))
+
+	r.Get("/api/info", middleware.AuthMiddleware(limiter.Limit(api.GetInfo)))
+	r.Get("/api/buy/{item}", middleware.AuthMiddleware(limiter.Limit(api.BuyItem)))
+	r.Get("/api/merch", middleware.AuthMiddleware(limiter.Limit(userHandler.ListMerchHandler)))
+	r.Get("/api/orders", middleware.AuthMiddleware(limiter.Limit(userHandler.ListOrdersHandler)))
+
+	r.Get("/api/wishlist", middleware.AuthMiddleware(limiter.Limit(userHandler.GetWishlistHandler)))
+	r.Post("/api/wishlist/{item}", middleware.AuthMiddleware(limiter.Limit(userHandler.AddWishlistItemHandler)))
+	r.Delete("/api/wishlist/{item}", middleware.AuthMiddleware(limiter.Limit(userHandler.RemoveWishlistItemHandler)))
+
+	r.Get("/api/notifications", middleware.AuthMiddleware(limiter.Limit(userHandler.ListNotificationsHandler)))
+	r.Post("/api/notifications/{id}/read", middleware.AuthMiddleware(limiter.Limit(userHandler.MarkNotificationReadHandler)))
+
+	r.Put("/api/admin/merch/{item}", middleware.AdminMiddleware(limiter.Limit(userHandler.UpdateMerchHandler)))
+	r.Put("/api/admin/merch/{item}/variants/{sku}", middleware.AdminMiddleware(limiter.Limit(userHandler.UpdateVariantHandler)))
+	r.Get("/api/admin/orders", middleware.AdminMiddleware(limiter.Limit(userHandler.ListAllOrdersHandler)))
+	r.Post("/api/admin/orders/{id}/advance", middleware.AdminMiddleware(limiter.Limit(userHandler.AdvanceOrderHandler)))
+	r.Get("/api/admin/promotions", middleware.AdminMiddleware(limiter.Limit(userHandler.ListPromotionsHandler)))
+	r.Post("/api/admin/promotions", middleware.AdminMiddleware(limiter.Limit(userHandler.CreatePromotionHandler)))
+	r.Get("/api/admin/coupons", middleware.AdminMiddleware(limiter.Limit(userHandler.ListCouponsHandler)))
+	r.Post("/api/admin/coupons", middleware.AdminMiddleware(limiter.Limit(userHandler.CreateCouponHandler)))
+	r.Post("/api/admin/users/{username}/unlock", middleware.AdminMiddleware(limiter.Limit(userHandler.UnlockUserHandler)))
+	r.Post("/api/admin/users/{username}/reset-token", middleware.AdminMiddleware(limiter.Limit(userHandler.IssueResetTokenHandler)))
 	return r
 }
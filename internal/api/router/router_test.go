@@ -0,0 +1,322 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"merchio/internal/api/handler"
+	"merchio/internal/api/openapi"
+	"merchio/internal/health"
+	"merchio/internal/model"
+	"merchio/internal/service"
+	"merchio/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) CreateUser(ctx context.Context, username, password string) (int64, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserService) Auth(ctx context.Context, username, password, ip string) (string, error) {
+	args := m.Called(ctx, username, password, ip)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserService) UnlockUser(ctx context.Context, adminID int, username string) (int64, error) {
+	args := m.Called(ctx, adminID, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error {
+	return m.Called(ctx, userID, currentPassword, newPassword).Error(0)
+}
+
+func (m *MockUserService) IssueResetToken(ctx context.Context, adminID int, username string) (*model.PasswordResetToken, error) {
+	args := m.Called(ctx, adminID, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockUserService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	return m.Called(ctx, token, newPassword).Error(0)
+}
+
+type MockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockTransferService) SendCoins(ctx context.Context, fromID int, toUser string, amount int) error {
+	return m.Called(ctx, fromID, toUser, amount).Error(0)
+}
+
+type MockInventoryService struct {
+	mock.Mock
+}
+
+func (m *MockInventoryService) BuyItem(ctx context.Context, req model.PurchaseRequest) (*model.Purchase, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Purchase), args.Error(1)
+}
+
+func (m *MockInventoryService) GetInfo(ctx context.Context, userID int) (*model.UserInfo, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserInfo), args.Error(1)
+}
+
+type mocks struct {
+	users     *MockUserService
+	transfers *MockTransferService
+	inventory *MockInventoryService
+}
+
+// specRouter ищет операции спецификации без учёта адреса сервера
+func specRouter(t *testing.T) routers.Router {
+	spec, err := openapi.GetSwagger()
+	require.NoError(t, err)
+	spec.Servers = nil
+	router, err := legacy.NewRouter(spec)
+	require.NoError(t, err)
+	return router
+}
+
+// Ответы настоящих обработчиков, пропущенные через роутер, должны соответствовать openapi.yaml
+func TestRouter_ConformsToSpec(t *testing.T) {
+	recipientID := uint(7)
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		auth           bool
+		setup          func(m mocks)
+		expectedStatus int
+	}{
+		{
+			name:   "Вход",
+			method: http.MethodPost,
+			path:   "/api/auth",
+			body:   `{"username":"alice","password":"passw0rd"}`,
+			setup: func(m mocks) {
+				m.users.On("Auth", mock.Anything, "alice", "passw0rd", "192.0.2.1").Return("token", nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Вход без имени",
+			method:         http.MethodPost,
+			path:           "/api/auth",
+			body:           `{"username":"","password":"passw0rd"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Вход пользователя, созданного до правил для имени",
+			method: http.MethodPost,
+			path:   "/api/auth",
+			body:   `{"username":"юл","password":"passw0rd"}`,
+			setup: func(m mocks) {
+				m.users.On("Auth", mock.Anything, "юл", "passw0rd", "192.0.2.1").Return("token", nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Неверный пароль",
+			method: http.MethodPost,
+			path:   "/api/auth",
+			body:   `{"username":"alice","password":"wrong"}`,
+			setup: func(m mocks) {
+				m.users.On("Auth", mock.Anything, "alice", "wrong", "192.0.2.1").Return("", service.ErrInvalidCredentials)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Вход заблокирован",
+			method: http.MethodPost,
+			path:   "/api/auth",
+			body:   `{"username":"alice","password":"guess"}`,
+			setup: func(m mocks) {
+				m.users.On("Auth", mock.Anything, "alice", "guess", "192.0.2.1").Return("", service.ErrAccountLocked)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:   "Информация о пользователе",
+			method: http.MethodGet,
+			path:   "/api/info",
+			auth:   true,
+			setup: func(m mocks) {
+				m.inventory.On("GetInfo", mock.Anything, 1).Return(&model.UserInfo{
+					Coins:     870,
+					Inventory: []model.InventoryItem{{Type: "hoody", Quantity: 1, SKU: "HOODY-GRY-M", Size: "M"}},
+					CoinHistory: model.CoinHistory{
+						Received: []model.ReceivedCoins{{FromUser: "bob", Amount: 20}},
+						Sent:     []model.SentCoins{},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Без токена",
+			method:         http.MethodGet,
+			path:           "/api/info",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Внутренняя ошибка",
+			method: http.MethodGet,
+			path:   "/api/info",
+			auth:   true,
+			setup: func(m mocks) {
+				m.inventory.On("GetInfo", mock.Anything, 1).Return(nil, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "Перевод монет",
+			method: http.MethodPost,
+			path:   "/api/sendCoin",
+			body:   `{"toUser":"bob","amount":10}`,
+			auth:   true,
+			setup: func(m mocks) {
+				m.transfers.On("SendCoins", mock.Anything, 1, "bob", 10).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Недостаточно монет для перевода",
+			method: http.MethodPost,
+			path:   "/api/sendCoin",
+			body:   `{"toUser":"bob","amount":10000}`,
+			auth:   true,
+			setup: func(m mocks) {
+				m.transfers.On("SendCoins", mock.Anything, 1, "bob", 10000).Return(service.ErrInsufficientCoins)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Покупка в подарок",
+			method: http.MethodGet,
+			path:   "/api/buy/hoody?variant=HOODY-GRY-M&recipient=bob",
+			auth:   true,
+			setup: func(m mocks) {
+				m.inventory.On("BuyItem", mock.Anything, model.PurchaseRequest{
+					UserID: 1, Item: "hoody", SKU: "HOODY-GRY-M", Recipient: "bob",
+				}).Return(&model.Purchase{
+					OrderID: 3, UserID: 1, RecipientID: &recipientID, Recipient: "bob", Item: "hoody",
+					SKU: "HOODY-GRY-M", ListPrice: 300, Price: 300, Balance: 700,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Товар не найден",
+			method: http.MethodGet,
+			path:   "/api/buy/unicorn",
+			auth:   true,
+			setup: func(m mocks) {
+				m.inventory.On("BuyItem", mock.Anything, mock.Anything).Return(nil, service.ErrItemNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Товар закончился",
+			method: http.MethodGet,
+			path:   "/api/buy/cup",
+			auth:   true,
+			setup: func(m mocks) {
+				m.inventory.On("BuyItem", mock.Anything, mock.Anything).Return(nil, service.ErrOutOfStock)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	spec := specRouter(t)
+	token, err := utils.GenerateToken(1, model.RoleUser)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks{new(MockUserService), new(MockTransferService), new(MockInventoryService)}
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			impl := handler.NewImplementation(m.users, m.transfers, nil, nil, nil, m.inventory, nil, nil)
+			r := NewRouter(impl, health.New(time.Second), nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+
+			route, pathParams, err := spec.FindRoute(req)
+			require.NoError(t, err)
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    req,
+					PathParams: pathParams,
+					Route:      route,
+				},
+				Status: rr.Code,
+				Header: rr.Header(),
+				Body:   io.NopCloser(bytes.NewReader(rr.Body.Bytes())),
+			})
+			assert.NoError(t, err)
+
+			m.users.AssertExpectations(t)
+			m.transfers.AssertExpectations(t)
+			m.inventory.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRouter_ServesSpec(t *testing.T) {
+	r := NewRouter(handler.NewImplementation(nil, nil, nil, nil, nil, nil, nil, nil), health.New(time.Second), nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.True(t, json.Valid(rr.Body.Bytes()))
+
+	spec, err := openapi3.NewLoader().LoadFromData(rr.Body.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate(context.Background()))
+	for _, path := range []string{"/api/auth", "/api/info", "/api/sendCoin", "/api/buy/{item}"} {
+		assert.NotNil(t, spec.Paths.Find(path), path)
+	}
+}
@@ -0,0 +1,70 @@
+// Package bootstrap собирает репозитории и сервисы поверх пула. Используется в main
+// и в интеграционных тестах, чтобы они поднимали одно и то же приложение.
+package bootstrap
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"merchio/internal/api/handler"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/passhash"
+	auditRepo "merchio/internal/repository/audit"
+	inventoryRepo "merchio/internal/repository/inventory"
+	loginRepo "merchio/internal/repository/login"
+	merchRepo "merchio/internal/repository/merch"
+	notificationRepo "merchio/internal/repository/notification"
+	orderRepo "merchio/internal/repository/order"
+	promotionRepo "merchio/internal/repository/promotion"
+	resetRepo "merchio/internal/repository/reset"
+	transferRepo "merchio/internal/repository/transfer"
+	userRepo "merchio/internal/repository/user"
+	wishlistRepo "merchio/internal/repository/wishlist"
+	inventoryServ "merchio/internal/service/inventory"
+	merchServ "merchio/internal/service/merch"
+	notificationServ "merchio/internal/service/notification"
+	orderServ "merchio/internal/service/order"
+	promotionServ "merchio/internal/service/promotion"
+	transferServ "merchio/internal/service/transfer"
+	userServ "merchio/internal/service/user"
+	wishlistServ "merchio/internal/service/wishlist"
+)
+
+type Options struct {
+	Lockout       userServ.LockoutPolicy
+	Hasher        passhash.Hasher
+	ResetTokenTTL time.Duration
+	Tx            pg.TxOptions
+}
+
+// NewHandler создаёт репозитории и сервисы и возвращает HTTP-обработчики API
+func NewHandler(pool *pgxpool.Pool, opts Options) *handler.Implementation {
+	users := userRepo.NewRepository(pool)
+	merch := merchRepo.NewRepository(pool)
+	wishlists := wishlistRepo.NewRepository(pool)
+	notifications := notificationRepo.NewRepository(pool)
+	inventory := inventoryRepo.NewRepository(pool)
+	orders := orderRepo.NewRepository(pool)
+	promotions := promotionRepo.NewRepository(pool)
+	transfers := transferRepo.NewRepository(pool)
+	txManager := pg.NewTxManager(pool, opts.Tx)
+
+	userService := userServ.NewService(users, loginRepo.NewRepository(pool), auditRepo.NewRepository(pool),
+		resetRepo.NewRepository(pool), txManager, userServ.Options{
+			Lockout:       opts.Lockout,
+			Hasher:        opts.Hasher,
+			ResetTokenTTL: opts.ResetTokenTTL,
+		})
+
+	return handler.NewImplementation(
+		userService,
+		transferServ.NewService(transfers, txManager),
+		merchServ.NewService(merch, wishlists, notifications, txManager),
+		wishlistServ.NewService(users, merch, wishlists),
+		notificationServ.NewService(notifications),
+		inventoryServ.NewService(users, inventory, transfers, notifications, txManager),
+		orderServ.NewService(orders, notifications, txManager),
+		promotionServ.NewService(merch, promotions),
+	)
+}
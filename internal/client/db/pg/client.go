@@ -1,22 +1,121 @@
-package db
+// Package pg - единая точка подключения к Postgres: настройки пула, таймаут запросов,
+// повтор подключения при старте и общий помощник транзакций для репозиториев.
+package pg
 
 import (
 	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/pkg/errors"
 )
 
-type pgClient struct {
-	dbc *pgxpool.Pool
+// maxConnectBackoff - верхняя граница паузы между попытками подключения
+const maxConnectBackoff = 10 * time.Second
+
+// Options - настройки пула. Нулевые значения оставляют умолчания pgx
+// (или параметры pool_max_conns и т.п. из DSN).
+type Options struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	// StatementTimeout - statement_timeout для каждого соединения, 0 - без ограничения
+	StatementTimeout time.Duration
+	// ConnectAttempts - сколько раз пробовать подключиться при старте, пауза между попытками
+	// начинается с ConnectBackoff и удваивается
+	ConnectAttempts int
+	ConnectBackoff  time.Duration
+	// Configure - дополнительная настройка пула перед подключением, например трассировка
+	Configure func(*pgxpool.Config)
+}
+
+type Client struct {
+	pool *pgxpool.Pool
+}
+
+// Connect подключается к базе, повторяя попытки, пока база поднимается
+func Connect(ctx context.Context, dsn string, opts Options) (*Client, error) {
+	cfg, err := opts.poolConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool *pgxpool.Pool
+	err = retry(ctx, opts.ConnectAttempts, opts.ConnectBackoff, func() error {
+		pool, err = pgxpool.ConnectConfig(ctx, cfg)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to db")
+	}
+	return &Client{pool: pool}, nil
 }
 
-func NewPostgresClient(ctx context.Context, dsn string) (*pgClient, error) {
-	dbc, err := pgxpool.Connect(ctx, dsn)
+func (o Options) poolConfig(dsn string) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
-		return nil, errors.Errorf("failed to connect to db: %v", err)
+		return nil, errors.Wrap(err, "parse postgres dsn")
+	}
+	if o.MaxConns > 0 {
+		cfg.MaxConns = o.MaxConns
+	}
+	if o.MinConns > 0 {
+		cfg.MinConns = o.MinConns
+	}
+	if o.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = o.MaxConnLifetime
 	}
+	if o.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = o.MaxConnIdleTime
+	}
+	if o.StatementTimeout > 0 {
+		cfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(o.StatementTimeout.Milliseconds(), 10)
+	}
+	if o.Configure != nil {
+		o.Configure(cfg)
+	}
+	return cfg, nil
+}
+
+// retry вызывает fn до attempts раз, удваивая паузу между попытками
+func retry(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for i := 1; ; i++ {
+		if err = fn(); err == nil || i >= attempts {
+			return err
+		}
+		slog.WarnContext(ctx, "postgres is not available, retrying",
+			"attempt", i, "attempts", attempts, "backoff", backoff, "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
+
+// Pool - пул для репозиториев, миграций и проверок готовности
+func (c *Client) Pool() *pgxpool.Pool {
+	return c.pool
+}
+
+func (c *Client) WithTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return WithTx(ctx, c.pool, fn)
+}
 
-	return &pgClient{
-		dbc: dbc,
-	}, nil
+func (c *Client) Close() {
+	c.pool.Close()
 }
@@ -0,0 +1,32 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Beginner - то, что умеет начать транзакцию: *pgxpool.Pool, pgx.Tx (точка сохранения) или мок
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// WithTx выполняет fn в транзакции: фиксирует её, если fn вернула nil, и откатывает
+// при ошибке или панике. Ошибка fn возвращается как есть, чтобы вызывающий мог сравнить её с errors.Is.
+// Внутри TxManager.Do транзакция открывается точкой сохранения во внешней.
+func WithTx(ctx context.Context, db Beginner, fn func(tx pgx.Tx) error) error {
+	if outer, ok := txFromContext(ctx); ok {
+		db = outer
+	}
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// После Commit откат ничего не делает
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,83 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTx(t *testing.T) {
+	errFn := errors.New("ошибка в транзакции")
+
+	tests := []struct {
+		name        string
+		setup       func(mock pgxmock.PgxPoolIface)
+		fn          func(tx pgx.Tx) error
+		expectedErr error
+	}{
+		{
+			name: "Успех фиксирует транзакцию",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectCommit()
+			},
+			fn: func(tx pgx.Tx) error {
+				_, err := tx.Exec(context.Background(), "UPDATE users SET coins = 0")
+				return err
+			},
+		},
+		{
+			name: "Ошибка откатывает и возвращается как есть",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn:          func(tx pgx.Tx) error { return errFn },
+			expectedErr: errFn,
+		},
+		{
+			name: "Ошибка начала транзакции",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin().WillReturnError(errFn)
+			},
+			fn:          func(tx pgx.Tx) error { t.Fatal("fn must not run"); return nil },
+			expectedErr: errFn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.setup(mock)
+
+			err = WithTx(context.Background(), mock, tt.fn)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithTx_RollsBackOnPanic(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.Panics(t, func() {
+		_ = WithTx(context.Background(), mock, func(tx pgx.Tx) error { panic("boom") })
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,122 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Коды ошибок Postgres, после которых транзакцию можно повторить целиком
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+type txKey struct{}
+
+// Querier - общее у пула и транзакции, ему же удовлетворяет pgxmock.PgxPoolIface.
+// Репозитории зависят только от него и выполняют запросы через Conn(ctx, db),
+// не зная, идут ли они внутри транзакции TxManager.
+type Querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Conn - транзакция, открытая TxManager.Do выше по стеку, или db, если её нет
+func Conn(ctx context.Context, db Querier) Querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+var (
+	_ Querier = (*pgxpool.Pool)(nil)
+	_ Querier = pgx.Tx(nil)
+)
+
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// TxBeginner - то, что открывает транзакцию с заданной изоляцией, например *pgxpool.Pool
+type TxBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+type TxOptions struct {
+	// IsoLevel - уровень изоляции внешних транзакций, пусто - умолчание базы (read committed)
+	IsoLevel pgx.TxIsoLevel
+	// MaxRetries - сколько раз повторить транзакцию после ошибки сериализации или взаимоблокировки
+	MaxRetries int
+}
+
+// TxManager - единица работы поверх нескольких репозиториев: все запросы с ctx из Do
+// идут в одну транзакцию, вложенный Do становится точкой сохранения.
+type TxManager struct {
+	db   TxBeginner
+	opts TxOptions
+}
+
+func NewTxManager(db TxBeginner, opts TxOptions) *TxManager {
+	return &TxManager{db: db, opts: opts}
+}
+
+// Do выполняет fn в транзакции. Внешняя транзакция повторяется при ошибке сериализации,
+// поэтому fn не должна иметь побочных эффектов вне базы. Ошибка fn возвращается как есть.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Откат точки сохранения не трогает внешнюю транзакцию, повторять её здесь нельзя:
+	// после ошибки сериализации вся внешняя транзакция уже недействительна
+	if tx, ok := txFromContext(ctx); ok {
+		return WithTx(ctx, tx, func(savepoint pgx.Tx) error {
+			return fn(context.WithValue(ctx, txKey{}, savepoint))
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.do(ctx, fn)
+		if err == nil || attempt > m.opts.MaxRetries || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+		slog.DebugContext(ctx, "transaction conflict, retrying", "attempt", attempt, "error", err)
+	}
+}
+
+func (m *TxManager) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := m.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: m.opts.IsoLevel})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// IsRetryable - транзакция прервана из-за конкурентной транзакции и может пройти при повторе
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
+
+// ParseIsoLevel разбирает уровень изоляции из конфигурации: read committed, repeatable read, serializable
+func ParseIsoLevel(s string) (pgx.TxIsoLevel, error) {
+	switch level := pgx.TxIsoLevel(s); level {
+	case pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable:
+		return level, nil
+	default:
+		return "", errors.New("expected read committed, repeatable read or serializable")
+	}
+}
@@ -0,0 +1,143 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Querier = pgxmock.PgxPoolIface(nil)
+
+func newMockPool(t *testing.T) pgxmock.PgxPoolIface {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+	return mock
+}
+
+func TestTxManager_Do(t *testing.T) {
+	mock := newMockPool(t)
+	manager := NewTxManager(mock, TxOptions{})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("INSERT INTO coin_transactions").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	// Два «репозитория» работают через Conn и попадают в одну транзакцию
+	err := manager.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := Conn(ctx, mock).Exec(ctx, "UPDATE users SET coins = coins - 1"); err != nil {
+			return err
+		}
+		_, err := Conn(ctx, mock).Exec(ctx, "INSERT INTO coin_transactions DEFAULT VALUES")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_NestedUsesSavepoint(t *testing.T) {
+	mock := newMockPool(t)
+	manager := NewTxManager(mock, TxOptions{})
+	errInner := errors.New("ошибка во вложенной транзакции")
+
+	mock.ExpectBegin()
+	mock.ExpectBegin()    // SAVEPOINT
+	mock.ExpectRollback() // ROLLBACK TO SAVEPOINT
+	mock.ExpectCommit()
+
+	err := manager.Do(context.Background(), func(ctx context.Context) error {
+		// Откат вложенной транзакции не отменяет внешнюю
+		assert.ErrorIs(t, manager.Do(ctx, func(ctx context.Context) error { return errInner }), errInner)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_WithTxJoinsOuterTransaction(t *testing.T) {
+	mock := newMockPool(t)
+	manager := NewTxManager(mock, TxOptions{})
+
+	mock.ExpectBegin()
+	mock.ExpectBegin() // SAVEPOINT из WithTx репозитория
+	mock.ExpectCommit()
+	mock.ExpectCommit()
+
+	err := manager.Do(context.Background(), func(ctx context.Context) error {
+		return WithTx(ctx, mock, func(tx pgx.Tx) error { return nil })
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_Retries(t *testing.T) {
+	serialization := &pgconn.PgError{Code: codeSerializationFailure}
+
+	tests := []struct {
+		name          string
+		maxRetries    int
+		failures      int
+		failWith      error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{name: "Повтор после ошибки сериализации", maxRetries: 2, failures: 1, failWith: serialization, expectedCalls: 2},
+		{name: "Повтор после взаимоблокировки", maxRetries: 2, failures: 2, failWith: &pgconn.PgError{Code: codeDeadlockDetected}, expectedCalls: 3},
+		{name: "Повторы кончились", maxRetries: 1, failures: 5, failWith: serialization, expectedCalls: 2, expectedErr: serialization},
+		{name: "Прочие ошибки не повторяются", maxRetries: 3, failures: 5, failWith: pgx.ErrNoRows, expectedCalls: 1, expectedErr: pgx.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockPool(t)
+			manager := NewTxManager(mock, TxOptions{IsoLevel: pgx.Serializable, MaxRetries: tt.maxRetries})
+			for i := 1; i <= tt.expectedCalls; i++ {
+				mock.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.Serializable})
+				if i <= tt.failures {
+					mock.ExpectRollback()
+				} else {
+					mock.ExpectCommit()
+				}
+			}
+
+			calls := 0
+			err := manager.Do(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return tt.failWith
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(&pgconn.PgError{Code: codeSerializationFailure}))
+	assert.True(t, IsRetryable(errors.Join(errors.New("transfer"), &pgconn.PgError{Code: codeDeadlockDetected})))
+	assert.False(t, IsRetryable(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, IsRetryable(errors.New("connection refused")))
+}
+
+func TestParseIsoLevel(t *testing.T) {
+	level, err := ParseIsoLevel("serializable")
+	require.NoError(t, err)
+	assert.Equal(t, pgx.Serializable, level)
+
+	_, err = ParseIsoLevel("snapshot")
+	assert.Error(t, err)
+}
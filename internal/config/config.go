@@ -0,0 +1,365 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"merchio/internal/client/db/pg"
+	"merchio/internal/logger"
+	"merchio/internal/passhash"
+	"merchio/internal/ratelimit"
+)
+
+// Config - настройки сервиса. Источники в порядке приоритета: флаги, переменные окружения,
+// файл конфигурации (формат .env), значения по умолчанию.
+//
+// Теги полей:
+//
+//	env     - ключ в окружении и в файле
+//	flag    - имя флага командной строки
+//	default - значение по умолчанию
+//	secret  - не выводить значение целиком
+type Config struct {
+	HTTPAddr       string `env:"HTTP_ADDR" flag:"http-addr" default:":8080" usage:"address the HTTP server listens on"`
+	PostgresDSN    string `env:"POSTGRES_CONN" flag:"postgres-dsn" secret:"true" usage:"postgres connection string"`
+	MigrateOnStart bool   `env:"MIGRATE_ON_START" flag:"migrate-on-start" default:"true" usage:"apply pending migrations at startup"`
+
+	DBMaxConns         int           `env:"DB_MAX_CONNS" flag:"db-max-conns" default:"10" usage:"maximum size of the postgres connection pool"`
+	DBMinConns         int           `env:"DB_MIN_CONNS" flag:"db-min-conns" default:"0" usage:"connections kept open even when idle"`
+	DBMaxConnLifetime  time.Duration `env:"DB_MAX_CONN_LIFETIME" flag:"db-max-conn-lifetime" default:"1h" usage:"close connections older than this"`
+	DBMaxConnIdleTime  time.Duration `env:"DB_MAX_CONN_IDLE_TIME" flag:"db-max-conn-idle-time" default:"30m" usage:"close connections idle for longer than this"`
+	DBStatementTimeout time.Duration `env:"DB_STATEMENT_TIMEOUT" flag:"db-statement-timeout" default:"30s" usage:"postgres statement_timeout for every connection, 0 disables it"`
+	DBConnectAttempts  int           `env:"DB_CONNECT_ATTEMPTS" flag:"db-connect-attempts" default:"5" usage:"how many times to try connecting to postgres at startup"`
+	DBConnectBackoff   time.Duration `env:"DB_CONNECT_BACKOFF" flag:"db-connect-backoff" default:"500ms" usage:"first pause between connection attempts, doubled up to 10s"`
+	DBTxIsolation      string        `env:"DB_TX_ISOLATION" flag:"db-tx-isolation" default:"read committed" usage:"isolation of service transactions: read committed, repeatable read or serializable"`
+	DBTxRetries        int           `env:"DB_TX_RETRIES" flag:"db-tx-retries" default:"3" usage:"how many times to retry a transaction after a serialization failure or deadlock"`
+
+	ShutdownTimeout    time.Duration `env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"15s" usage:"how long to drain in-flight requests on shutdown"`
+	ShutdownDrainDelay time.Duration `env:"SHUTDOWN_DRAIN_DELAY" flag:"shutdown-drain-delay" default:"5s" usage:"how long to keep serving after readiness turns off, counted within SHUTDOWN_TIMEOUT"`
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" flag:"health-check-timeout" default:"2s" usage:"timeout for each readiness dependency check"`
+
+	LogLevel string `env:"LOG_LEVEL" flag:"log-level" default:"info" usage:"minimum log level: debug, info, warn or error"`
+
+	TracingExporter     string `env:"TRACING_EXPORTER" flag:"tracing-exporter" default:"none" usage:"span exporter: none, stdout or otlp"`
+	TracingOTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT" flag:"tracing-otlp-endpoint" default:"localhost:4318" usage:"OTLP/HTTP collector host:port"`
+	TracingOTLPInsecure bool   `env:"TRACING_OTLP_INSECURE" flag:"tracing-otlp-insecure" default:"true" usage:"send OTLP over plain HTTP"`
+
+	RateLimitAnonymous string `env:"RATE_LIMIT_ANONYMOUS" flag:"rate-limit-anonymous" default:"10/1m" usage:"per-IP limit for routes without a token, e.g. 10/1m, or off"`
+	RateLimitUser      string `env:"RATE_LIMIT_USER" flag:"rate-limit-user" default:"20/1s" usage:"per-user limit for authenticated routes, or off"`
+	RateLimitRoutes    string `env:"RATE_LIMIT_ROUTES" flag:"rate-limit-routes" usage:"per-route overrides: 'POST /api/sendCoin=5/1s, GET /api/buy/{item}=10/1s'"`
+
+	LoginMaxFailures int           `env:"LOGIN_MAX_FAILURES" flag:"login-max-failures" default:"5" usage:"failed logins from one IP before the username is locked out"`
+	LoginLockout     time.Duration `env:"LOGIN_LOCKOUT" flag:"login-lockout" default:"1m" usage:"first lockout duration, doubled on each further failure"`
+	LoginMaxLockout  time.Duration `env:"LOGIN_MAX_LOCKOUT" flag:"login-max-lockout" default:"1h" usage:"longest lockout, failures older than this are forgotten"`
+
+	PasswordHash      string `env:"PASSWORD_HASH" flag:"password-hash" default:"argon2id" usage:"algorithm for new password hashes: argon2id or bcrypt, hashes of the other one are upgraded on login"`
+	BcryptCost        int    `env:"BCRYPT_COST" flag:"bcrypt-cost" default:"10" usage:"bcrypt cost for new password hashes, older hashes are upgraded on login"`
+	Argon2Memory      int    `env:"ARGON2_MEMORY" flag:"argon2-memory" default:"65536" usage:"argon2id memory in KiB"`
+	Argon2Iterations  int    `env:"ARGON2_ITERATIONS" flag:"argon2-iterations" default:"3" usage:"argon2id passes over memory"`
+	Argon2Parallelism int    `env:"ARGON2_PARALLELISM" flag:"argon2-parallelism" default:"2" usage:"argon2id lanes"`
+
+	PasswordResetTTL time.Duration `env:"PASSWORD_RESET_TTL" flag:"password-reset-ttl" default:"1h" usage:"how long an admin-issued password reset token stays valid"`
+}
+
+// LookupFunc - источник переменных окружения, в main это os.LookupEnv
+type LookupFunc func(key string) (string, bool)
+
+// Load собирает конфигурацию и возвращает аргументы, оставшиеся после флагов (подкоманду)
+func Load(args []string, lookupEnv LookupFunc) (*Config, []string, error) {
+	fs := flag.NewFlagSet("merchio", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFile := fs.String("config", "", "path to a KEY=VALUE config file, CONFIG_FILE in env")
+
+	fields := fieldsOf(&Config{})
+	flagValues := make(map[string]*flagValue, len(fields))
+	for _, f := range fields {
+		v := &flagValue{isBool: f.value.Kind() == reflect.Bool}
+		flagValues[f.flag] = v
+		fs.Var(v, f.flag, f.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile == "" {
+		*configFile, _ = lookupEnv("CONFIG_FILE")
+	}
+	var fileValues map[string]string
+	if *configFile != "" {
+		var err error
+		if fileValues, err = godotenv.Read(*configFile); err != nil {
+			return nil, nil, errors.Wrapf(err, "read config file %s", *configFile)
+		}
+	}
+
+	cfg := &Config{}
+	var problems []string
+	for _, f := range fieldsOf(cfg) {
+		raw, ok := f.defaultValue, f.defaultValue != ""
+		if v, found := fileValues[f.env]; found {
+			raw, ok = v, true
+		}
+		if v, found := lookupEnv(f.env); found {
+			raw, ok = v, true
+		}
+		if v := flagValues[f.flag]; v.set {
+			raw, ok = v.raw, true
+		}
+		if !ok {
+			continue
+		}
+		if err := f.parse(raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", f.env, err))
+		}
+	}
+
+	problems = append(problems, cfg.problems()...)
+	if len(problems) > 0 {
+		return nil, nil, errors.Errorf("invalid config:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return cfg, fs.Args(), nil
+}
+
+// problems проверяет значения, чтобы при старте показать все ошибки сразу, а не по одной
+func (c *Config) problems() []string {
+	var problems []string
+	if c.HTTPAddr == "" {
+		problems = append(problems, "HTTP_ADDR must not be empty")
+	}
+	if c.PostgresDSN == "" {
+		problems = append(problems, "POSTGRES_CONN is required")
+	} else if _, err := pgxpool.ParseConfig(c.PostgresDSN); err != nil {
+		problems = append(problems, "POSTGRES_CONN is not a valid connection string")
+	}
+	if c.ShutdownTimeout <= 0 {
+		problems = append(problems, "SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.ShutdownDrainDelay < 0 || c.ShutdownDrainDelay >= c.ShutdownTimeout {
+		problems = append(problems, "SHUTDOWN_DRAIN_DELAY must be between 0 and SHUTDOWN_TIMEOUT")
+	}
+	if _, err := logger.ParseLevel(c.LogLevel); err != nil {
+		problems = append(problems, "LOG_LEVEL must be one of debug, info, warn, error")
+	}
+	switch c.TracingExporter {
+	case "none", "stdout", "otlp":
+	default:
+		problems = append(problems, "TRACING_EXPORTER must be one of none, stdout, otlp")
+	}
+	if c.TracingExporter == "otlp" && c.TracingOTLPEndpoint == "" {
+		problems = append(problems, "TRACING_OTLP_ENDPOINT is required for the otlp exporter")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		problems = append(problems, "HEALTH_CHECK_TIMEOUT must be positive")
+	}
+	if c.DBMaxConns <= 0 || c.DBMinConns < 0 || c.DBMinConns > c.DBMaxConns {
+		problems = append(problems, "DB_MAX_CONNS must be positive and DB_MIN_CONNS between 0 and DB_MAX_CONNS")
+	}
+	if c.DBMaxConnLifetime <= 0 || c.DBMaxConnIdleTime <= 0 {
+		problems = append(problems, "DB_MAX_CONN_LIFETIME and DB_MAX_CONN_IDLE_TIME must be positive")
+	}
+	if c.DBStatementTimeout < 0 {
+		problems = append(problems, "DB_STATEMENT_TIMEOUT must not be negative")
+	}
+	if c.DBConnectAttempts <= 0 || c.DBConnectBackoff < 0 {
+		problems = append(problems, "DB_CONNECT_ATTEMPTS must be positive and DB_CONNECT_BACKOFF not negative")
+	}
+	if _, err := pg.ParseIsoLevel(c.DBTxIsolation); err != nil {
+		problems = append(problems, "DB_TX_ISOLATION: "+err.Error())
+	}
+	if c.DBTxRetries < 0 {
+		problems = append(problems, "DB_TX_RETRIES must not be negative")
+	}
+	if c.LoginMaxFailures <= 0 {
+		problems = append(problems, "LOGIN_MAX_FAILURES must be positive")
+	}
+	if c.LoginLockout <= 0 || c.LoginMaxLockout < c.LoginLockout {
+		problems = append(problems, "LOGIN_LOCKOUT must be positive and not greater than LOGIN_MAX_LOCKOUT")
+	}
+	if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+		problems = append(problems, fmt.Sprintf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+	switch c.PasswordHash {
+	case passhash.AlgorithmArgon2id, passhash.AlgorithmBcrypt:
+	default:
+		problems = append(problems, "PASSWORD_HASH must be one of argon2id, bcrypt")
+	}
+	// Нижняя граница памяти - минимум Argon2 (8 КиБ на поток), верхняя - 4 ГиБ
+	if c.Argon2Memory < 8*c.Argon2Parallelism || c.Argon2Memory > 4*1024*1024 {
+		problems = append(problems, "ARGON2_MEMORY must be between 8 KiB per lane and 4 GiB")
+	}
+	if c.Argon2Iterations <= 0 {
+		problems = append(problems, "ARGON2_ITERATIONS must be positive")
+	}
+	if c.Argon2Parallelism <= 0 || c.Argon2Parallelism > 255 {
+		problems = append(problems, "ARGON2_PARALLELISM must be between 1 and 255")
+	}
+	if c.PasswordResetTTL <= 0 {
+		problems = append(problems, "PASSWORD_RESET_TTL must be positive")
+	}
+	if _, err := ratelimit.ParseLimit(c.RateLimitAnonymous); err != nil {
+		problems = append(problems, "RATE_LIMIT_ANONYMOUS: "+err.Error())
+	}
+	if _, err := ratelimit.ParseLimit(c.RateLimitUser); err != nil {
+		problems = append(problems, "RATE_LIMIT_USER: "+err.Error())
+	}
+	if _, err := ratelimit.ParseRoutes(c.RateLimitRoutes); err != nil {
+		problems = append(problems, "RATE_LIMIT_ROUTES: "+err.Error())
+	}
+	return problems
+}
+
+// RateLimitPolicy собирает лимиты из настроек, значения уже проверены в Load
+func (c *Config) RateLimitPolicy() ratelimit.Policy {
+	anonymous, _ := ratelimit.ParseLimit(c.RateLimitAnonymous)
+	user, _ := ratelimit.ParseLimit(c.RateLimitUser)
+	routes, _ := ratelimit.ParseRoutes(c.RateLimitRoutes)
+	return ratelimit.Policy{Anonymous: anonymous, User: user, Routes: routes}
+}
+
+// PostgresOptions - настройки пула из конфигурации, значения уже проверены в Load
+func (c *Config) PostgresOptions() pg.Options {
+	return pg.Options{
+		MaxConns:         int32(c.DBMaxConns),
+		MinConns:         int32(c.DBMinConns),
+		MaxConnLifetime:  c.DBMaxConnLifetime,
+		MaxConnIdleTime:  c.DBMaxConnIdleTime,
+		StatementTimeout: c.DBStatementTimeout,
+		ConnectAttempts:  c.DBConnectAttempts,
+		ConnectBackoff:   c.DBConnectBackoff,
+	}
+}
+
+// TxOptions - настройки транзакций сервисов, значения уже проверены в Load
+func (c *Config) TxOptions() pg.TxOptions {
+	level, _ := pg.ParseIsoLevel(c.DBTxIsolation)
+	return pg.TxOptions{IsoLevel: level, MaxRetries: c.DBTxRetries}
+}
+
+// PasswordHasher собирает хешер паролей из настроек, значения уже проверены в Load
+func (c *Config) PasswordHasher() *passhash.Set {
+	hasher, _ := passhash.ForAlgorithm(c.PasswordHash,
+		passhash.Bcrypt{Cost: c.BcryptCost},
+		passhash.Argon2id{
+			Memory:      uint32(c.Argon2Memory),
+			Iterations:  uint32(c.Argon2Iterations),
+			Parallelism: uint8(c.Argon2Parallelism),
+		})
+	return hasher
+}
+
+// String выводит действующую конфигурацию, секреты скрыты
+func (c Config) String() string {
+	var b strings.Builder
+	for i, f := range fieldsOf(&c) {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		value := f.format()
+		if f.secret {
+			value = redact(value)
+		}
+		fmt.Fprintf(&b, "%s=%s", f.env, value)
+	}
+	return b.String()
+}
+
+// redact оставляет у URL всё, кроме пароля, остальные секреты скрывает полностью
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+	return "***"
+}
+
+type field struct {
+	env          string
+	flag         string
+	usage        string
+	defaultValue string
+	secret       bool
+	value        reflect.Value
+}
+
+func fieldsOf(c *Config) []field {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag
+		fields = append(fields, field{
+			env:          tag.Get("env"),
+			flag:         tag.Get("flag"),
+			usage:        tag.Get("usage"),
+			defaultValue: tag.Get("default"),
+			secret:       tag.Get("secret") == "true",
+			value:        v.Field(i),
+		})
+	}
+	return fields
+}
+
+func (f field) parse(raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch f.value.Interface().(type) {
+	case string:
+		f.value.SetString(raw)
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.Errorf("expected true or false, got %q", raw)
+		}
+		f.value.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.Errorf("expected an integer, got %q", raw)
+		}
+		f.value.SetInt(int64(n))
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.Errorf("expected a duration like 30s, got %q", raw)
+		}
+		f.value.SetInt(int64(d))
+	default:
+		return errors.Errorf("unsupported config type %s", f.value.Type())
+	}
+	return nil
+}
+
+func (f field) format() string {
+	if d, ok := f.value.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	return fmt.Sprint(f.value.Interface())
+}
+
+// flagValue запоминает, был ли флаг передан явно, чтобы не перетирать env значением по умолчанию
+type flagValue struct {
+	raw    string
+	set    bool
+	isBool bool
+}
+
+func (v *flagValue) String() string { return v.raw }
+
+func (v *flagValue) Set(s string) error {
+	v.raw, v.set = s, true
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool { return v.isBool }
@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pinger - то, что умеет проверить соединение, например *pgxpool.Pool
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PendingCounter - источник числа неприменённых миграций, например *migrations.Migrator
+type PendingCounter interface {
+	Pending(ctx context.Context) (int, error)
+}
+
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+// MigrationsCheck не готов, пока схема отстаёт от миграций в бинарнике
+func MigrationsCheck(m PendingCounter) CheckFunc {
+	return func(ctx context.Context) error {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pendingStub struct {
+	pending int
+	err     error
+}
+
+func (p pendingStub) Pending(ctx context.Context) (int, error) {
+	return p.pending, p.err
+}
+
+func ok(ctx context.Context) error { return nil }
+
+func readiness(t *testing.T, h *Health) (int, Report) {
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]CheckFunc
+		expectedCode   int
+		expectedStatus map[string]string
+	}{
+		{
+			name: "Все зависимости доступны",
+			checks: map[string]CheckFunc{
+				"postgres":   ok,
+				"migrations": MigrationsCheck(pendingStub{}),
+			},
+			expectedCode:   http.StatusOK,
+			expectedStatus: map[string]string{"postgres": StatusOK, "migrations": StatusOK},
+		},
+		{
+			name: "БД недоступна",
+			checks: map[string]CheckFunc{
+				"postgres":   func(ctx context.Context) error { return errors.New("connection refused") },
+				"migrations": MigrationsCheck(pendingStub{}),
+			},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: map[string]string{"postgres": StatusUnavailable, "migrations": StatusOK},
+		},
+		{
+			name: "Есть неприменённые миграции",
+			checks: map[string]CheckFunc{
+				"postgres":   ok,
+				"migrations": MigrationsCheck(pendingStub{pending: 2}),
+			},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: map[string]string{"postgres": StatusOK, "migrations": StatusUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(time.Second)
+			for name, fn := range tt.checks {
+				h.Register(name, fn)
+			}
+
+			code, report := readiness(t, h)
+
+			assert.Equal(t, tt.expectedCode, code)
+			for name, status := range tt.expectedStatus {
+				assert.Equal(t, status, report.Checks[name].Status, name)
+			}
+		})
+	}
+}
+
+func TestReadiness_HidesErrorDetails(t *testing.T) {
+	h := New(time.Second)
+	h.Register("postgres", func(ctx context.Context) error {
+		return errors.New(`FATAL: password authentication failed for user "merchio"`)
+	})
+	h.Register("migrations", MigrationsCheck(pendingStub{pending: 3}))
+
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "password authentication")
+	assert.NotContains(t, rec.Body.String(), "pending migrations")
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, StatusUnavailable, report.Checks["migrations"].Status)
+	assert.GreaterOrEqual(t, report.Checks["migrations"].LatencyMs, 0.0)
+}
+
+func TestReadiness_CheckTimeout(t *testing.T) {
+	h := New(10 * time.Millisecond)
+	h.Register("postgres", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	code, report := readiness(t, h)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusUnavailable, report.Checks["postgres"].Status)
+}
+
+func TestReadiness_ShuttingDown(t *testing.T) {
+	called := false
+	h := New(time.Second)
+	h.Register("postgres", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	h.SetShuttingDown()
+
+	code, report := readiness(t, h)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusShuttingDown, report.Status)
+	assert.False(t, called)
+}
+
+func TestLiveness_IgnoresDependencies(t *testing.T) {
+	h := New(time.Second)
+	h.Register("postgres", func(ctx context.Context) error { return errors.New("down") })
+	h.SetShuttingDown()
+
+	rec := httptest.NewRecorder()
+	h.LivenessHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"merchio/internal/repository/login"
+	"merchio/internal/testdb"
+)
+
+func TestAuth_FirstLoginCreatesUser(t *testing.T) {
+	a := newAPI(t)
+
+	token := a.login("alice", "Password123")
+
+	assert.Equal(t, 1000, a.info(token).Coins)
+	// Повторный вход тем же паролем не создаёт пользователя заново
+	assert.NotEmpty(t, a.login("alice", "Password123"))
+}
+
+func TestAuth_LegacyUsername(t *testing.T) {
+	a := newAPI(t)
+	// Пользователь из времён до правил для имени: при регистрации такое имя уже не примут
+	hash, err := bcrypt.GenerateFromPassword([]byte("qwerty"), bcrypt.MinCost)
+	require.NoError(t, err)
+	_, err = a.pool.Exec(context.Background(), "INSERT INTO users (username, password) VALUES ($1, $2)", "юл", string(hash))
+	require.NoError(t, err)
+
+	token := a.login("юл", "qwerty")
+
+	assert.Equal(t, 1000, a.info(token).Coins)
+	assert.Equal(t, http.StatusBadRequest,
+		a.do(http.MethodPost, "/api/auth", "", map[string]string{"username": "ян", "password": "Password123"}, nil))
+}
+
+func TestAuth_WrongPassword(t *testing.T) {
+	a := newAPI(t)
+	a.login("alice", "Password123")
+
+	var resp errorResponse
+	status := a.do(http.MethodPost, "/api/auth", "", map[string]string{"username": "alice", "password": "Password124"}, &resp)
+
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.NotEmpty(t, resp.Errors)
+}
+
+func TestAuth_LocksOutAfterFailures(t *testing.T) {
+	a := newAPI(t)
+	a.login("alice", "Password123")
+
+	for i := 0; i < 3; i++ {
+		a.do(http.MethodPost, "/api/auth", "", map[string]string{"username": "alice", "password": "wrong-password1"}, nil)
+	}
+	status := a.do(http.MethodPost, "/api/auth", "", map[string]string{"username": "alice", "password": "Password123"}, nil)
+
+	// Даже верный пароль не принимается, пока пара имя+IP заблокирована
+	assert.Equal(t, http.StatusTooManyRequests, status)
+}
+
+func TestAuth_RequiresToken(t *testing.T) {
+	a := newAPI(t)
+
+	assert.Equal(t, http.StatusUnauthorized, a.do(http.MethodGet, "/api/info", "", nil, nil))
+	assert.Equal(t, http.StatusUnauthorized, a.do(http.MethodGet, "/api/info", "not-a-token", nil, nil))
+}
+
+func TestLoginAttempts_LockedUntilKeepsInstant(t *testing.T) {
+	pool := testdb.New(t)
+	ctx := context.Background()
+	attempts := login.NewRepository(pool)
+	// Приложение в поясе, отличном от UTC: срок должен вернуться тем же моментом
+	now := time.Now().In(time.FixedZone("UTC+3", 3*60*60))
+
+	_, err := attempts.RecordFailure(ctx, "alice", "10.0.0.1", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, attempts.Lock(ctx, "alice", "10.0.0.1", now.Add(time.Minute)))
+	got, err := attempts.Get(ctx, "alice", "10.0.0.1")
+	require.NoError(t, err)
+
+	require.NotNil(t, got.LockedUntil)
+	assert.WithinDuration(t, now.Add(time.Minute), *got.LockedUntil, time.Millisecond)
+	assert.True(t, got.Locked(now))
+	assert.False(t, got.Locked(now.Add(2*time.Minute)))
+}
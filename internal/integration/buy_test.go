@@ -0,0 +1,119 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type purchase struct {
+	Item    string `json:"item"`
+	SKU     string `json:"sku"`
+	Price   int    `json:"price"`
+	Balance int    `json:"balance"`
+}
+
+func TestBuy(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+
+	var resp purchase
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/cup", alice, nil, &resp))
+	assert.Equal(t, purchase{Item: "cup", Price: 20, Balance: 980}, resp)
+
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/hoody?variant=HOODY-GRY-XL", alice, nil, &resp))
+	assert.Equal(t, purchase{Item: "hoody", SKU: "HOODY-GRY-XL", Price: 320, Balance: 660}, resp)
+}
+
+func TestBuy_Errors(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "Неизвестный товар", path: "/api/buy/yacht", expectedStatus: http.StatusNotFound},
+		{name: "Товар с вариантами без варианта", path: "/api/buy/hoody", expectedStatus: http.StatusBadRequest},
+		{name: "Подарок неизвестному получателю", path: "/api/buy/cup?recipient=nobody", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, a.do(http.MethodGet, tt.path, alice, nil, nil))
+		})
+	}
+	assert.Equal(t, 1000, a.info(alice).Coins)
+}
+
+func TestBuy_InsufficientCoins(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/pink-hoody?variant=PHOODY-PNK-S", alice, nil, nil))
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/pink-hoody?variant=PHOODY-PNK-M", alice, nil, nil))
+
+	assert.Equal(t, http.StatusBadRequest, a.do(http.MethodGet, "/api/buy/pen", alice, nil, nil))
+	assert.Equal(t, 0, a.info(alice).Coins)
+}
+
+func TestBuy_Gift(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+	bob := a.login("bob", "Password123")
+
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/cup?recipient=bob", alice, nil, nil))
+
+	// Платит даритель, товар попадает в инвентарь получателя
+	assert.Equal(t, 980, a.info(alice).Coins)
+	bobInfo := a.info(bob)
+	assert.Equal(t, 1000, bobInfo.Coins)
+	if assert.Len(t, bobInfo.Inventory, 1) {
+		assert.Equal(t, "cup", bobInfo.Inventory[0].Type)
+	}
+
+	// Уведомление о подарке сохраняется в той же транзакции, что и покупка
+	var notifications struct {
+		Notifications []struct {
+			Kind    string `json:"kind"`
+			Message string `json:"message"`
+		} `json:"notifications"`
+	}
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/notifications", bob, nil, &notifications))
+	if assert.Len(t, notifications.Notifications, 1) {
+		assert.Equal(t, "gift", notifications.Notifications[0].Kind)
+		assert.Equal(t, "alice sent you a gift: cup", notifications.Notifications[0].Message)
+	}
+}
+
+func TestBuy_PromotionAndCouponWindows(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+	ctx := context.Background()
+	// Сроки заданы в поясе, отличном от UTC: сравниваться должны моменты, а не показания часов
+	now := time.Now().In(time.FixedZone("UTC+3", 3*60*60))
+	_, err := a.pool.Exec(ctx,
+		`INSERT INTO promotions (name, merch_id, kind, value, starts_at, ends_at)
+         SELECT 'cup week', id, 'fixed', 5, $1, $2 FROM merch_items WHERE name = 'cup'`,
+		now.Add(-time.Minute), now.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = a.pool.Exec(ctx,
+		`INSERT INTO coupons (code, kind, value, category, starts_at, ends_at) VALUES
+         ('FRESH', 'fixed', 3, 'accessories', $1, $2),
+         ('STALE', 'fixed', 3, 'accessories', $3, $4)`,
+		now.Add(-time.Minute), now.Add(time.Hour), now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, a.do(http.MethodGet, "/api/buy/cup?coupon=STALE", alice, nil, nil))
+
+	var resp purchase
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/cup?coupon=FRESH", alice, nil, &resp))
+	assert.Equal(t, purchase{Item: "cup", Price: 12, Balance: 988}, resp)
+}
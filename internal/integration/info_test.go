@@ -0,0 +1,40 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo_NewUser(t *testing.T) {
+	a := newAPI(t)
+
+	got := a.info(a.login("alice", "Password123"))
+
+	assert.Equal(t, 1000, got.Coins)
+	assert.Empty(t, got.Inventory)
+	assert.Empty(t, got.CoinHistory.Received)
+	assert.Empty(t, got.CoinHistory.Sent)
+}
+
+func TestInfo_AggregatesInventory(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/pen", alice, nil, nil))
+	}
+	require.Equal(t, http.StatusOK, a.do(http.MethodGet, "/api/buy/book", alice, nil, nil))
+
+	got := a.info(alice)
+	assert.Equal(t, 1000-3*10-50, got.Coins)
+	quantities := map[string]int{}
+	for _, item := range got.Inventory {
+		quantities[item.Type] += item.Quantity
+	}
+	assert.Equal(t, map[string]int{"pen": 3, "book": 1}, quantities)
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/migrations"
+	"merchio/internal/testdb"
+)
+
+// База, созданная init.sql до появления миграций: users уже есть, но без колонок, добавленных позже
+func TestMigrations_UpgradeLegacyUsers(t *testing.T) {
+	pool := testdb.New(t)
+	ctx := context.Background()
+	loaded, err := migrations.Load(migrations.Files)
+	require.NoError(t, err)
+	migrator := migrations.NewMigrator(pool, loaded)
+
+	_, err = migrator.To(ctx, 0)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `CREATE TABLE users (
+        id SERIAL PRIMARY KEY,
+        username VARCHAR(255) NOT NULL UNIQUE,
+        password VARCHAR(255) NOT NULL,
+        coins INTEGER DEFAULT 1000,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "INSERT INTO users (username, password) VALUES ('oldtimer', 'hash')")
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	var role string
+	require.NoError(t, pool.QueryRow(ctx, "SELECT role FROM users WHERE username = 'oldtimer'").Scan(&role))
+	assert.Equal(t, "user", role)
+}
+
+func TestMigrations_PendingIsReadOnly(t *testing.T) {
+	pool := testdb.New(t)
+	ctx := context.Background()
+	loaded, err := migrations.Load(migrations.Files)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "DROP TABLE schema_migrations")
+	require.NoError(t, err)
+
+	pending, err := migrations.NewMigrator(pool, loaded).Pending(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, len(loaded), pending)
+	// Проверка готовности не создаёт таблицу версий заново
+	var exists bool
+	require.NoError(t, pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists))
+	assert.False(t, exists)
+}
@@ -0,0 +1,63 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sendCoin(a *api, token, toUser string, amount int) int {
+	a.t.Helper()
+	return a.do(http.MethodPost, "/api/sendCoin", token, map[string]interface{}{"toUser": toUser, "amount": amount}, nil)
+}
+
+func TestSendCoin(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+	bob := a.login("bob", "Password123")
+
+	require.Equal(t, http.StatusOK, sendCoin(a, alice, "bob", 150))
+	require.Equal(t, http.StatusOK, sendCoin(a, bob, "alice", 50))
+
+	aliceInfo, bobInfo := a.info(alice), a.info(bob)
+	assert.Equal(t, 900, aliceInfo.Coins)
+	assert.Equal(t, 1100, bobInfo.Coins)
+	if assert.Len(t, aliceInfo.CoinHistory.Sent, 1) {
+		assert.Equal(t, "bob", aliceInfo.CoinHistory.Sent[0].ToUser)
+		assert.Equal(t, 150, aliceInfo.CoinHistory.Sent[0].Amount)
+	}
+	if assert.Len(t, bobInfo.CoinHistory.Received, 1) {
+		assert.Equal(t, "alice", bobInfo.CoinHistory.Received[0].FromUser)
+	}
+}
+
+func TestSendCoin_Errors(t *testing.T) {
+	a := newAPI(t)
+	alice := a.login("alice", "Password123")
+	a.login("bob", "Password123")
+
+	tests := []struct {
+		name           string
+		toUser         string
+		amount         int
+		expectedStatus int
+	}{
+		{name: "Не хватает монет", toUser: "bob", amount: 1001, expectedStatus: http.StatusBadRequest},
+		{name: "Неизвестный получатель", toUser: "nobody", amount: 10, expectedStatus: http.StatusNotFound},
+		{name: "Самому себе", toUser: "alice", amount: 10, expectedStatus: http.StatusBadRequest},
+		{name: "Неположительная сумма", toUser: "bob", amount: 0, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, sendCoin(a, alice, tt.toUser, tt.amount))
+		})
+	}
+
+	// Неудачные переводы не двигают монеты
+	assert.Equal(t, 1000, a.info(alice).Coins)
+}
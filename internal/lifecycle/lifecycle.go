@@ -0,0 +1,127 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook - подсистема с действиями при запуске и остановке. Любое из них можно не задавать.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle запускает хуки в порядке добавления и останавливает в обратном,
+// так что зависимые подсистемы (HTTP-сервер) закрываются раньше своих зависимостей (пул БД).
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+	failed  chan error
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{failed: make(chan error, 1)}
+}
+
+// Append регистрирует хук. Вызывать до Start.
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// Start выполняет OnStart всех хуков. Если один из них упал, уже запущенные останавливаются.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for _, h := range hooks {
+		if h.OnStart != nil {
+			if err := h.OnStart(ctx); err != nil {
+				startErr := fmt.Errorf("start %s: %w", h.Name, err)
+				if stopErr := l.Stop(ctx); stopErr != nil {
+					return fmt.Errorf("%w; %v", startErr, stopErr)
+				}
+				return startErr
+			}
+		}
+		l.mu.Lock()
+		l.started++
+		l.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop выполняет OnStop запущенных хуков в обратном порядке.
+// Ошибки не прерывают остановку, они собираются в одну.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	var problems []string
+	l.mu.Lock()
+	hooks := l.hooks[:l.started]
+	l.started = 0
+	l.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		if err := h.OnStop(ctx); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", h.Name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("shutdown: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Fail сообщает Run, что подсистема не может продолжать работу, и запускает остановку
+func (l *Lifecycle) Fail(err error) {
+	select {
+	case l.failed <- err:
+	default:
+	}
+}
+
+// Run запускает хуки, ждёт SIGINT/SIGTERM, отмены ctx или Fail и останавливает
+// приложение, давая на это не больше shutdownTimeout.
+func (l *Lifecycle) Run(ctx context.Context, shutdownTimeout time.Duration) error {
+	if err := l.Start(ctx); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var runErr error
+	select {
+	case sig := <-signals:
+		slog.Info("shutting down", "signal", sig.String())
+	case <-ctx.Done():
+		slog.Info("shutting down", "reason", ctx.Err())
+	case runErr = <-l.failed:
+		slog.Error("shutting down after failure", "error", runErr)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := l.Stop(stopCtx); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("%w; %v", runErr, err)
+		}
+		return err
+	}
+	return runErr
+}
@@ -0,0 +1,96 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingHook(name string, calls *[]string, startErr, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			*calls = append(*calls, "start "+name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+func TestLifecycle_StopsInReverseOrder(t *testing.T) {
+	var calls []string
+	l := New()
+	l.Append(recordingHook("db", &calls, nil, nil))
+	l.Append(recordingHook("http", &calls, nil, nil))
+
+	require.NoError(t, l.Start(context.Background()))
+	require.NoError(t, l.Stop(context.Background()))
+
+	assert.Equal(t, []string{"start db", "start http", "stop http", "stop db"}, calls)
+}
+
+func TestLifecycle_FailedStartStopsStartedHooks(t *testing.T) {
+	var calls []string
+	boom := errors.New("boom")
+	l := New()
+	l.Append(recordingHook("db", &calls, nil, nil))
+	l.Append(recordingHook("http", &calls, boom, nil))
+	l.Append(recordingHook("worker", &calls, nil, nil))
+
+	err := l.Start(context.Background())
+
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, []string{"start db", "start http", "stop db"}, calls)
+}
+
+func TestLifecycle_StopCollectsErrors(t *testing.T) {
+	var calls []string
+	l := New()
+	l.Append(recordingHook("db", &calls, nil, errors.New("close failed")))
+	l.Append(recordingHook("http", &calls, nil, errors.New("drain failed")))
+	require.NoError(t, l.Start(context.Background()))
+
+	err := l.Stop(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "http: drain failed")
+	assert.Contains(t, err.Error(), "db: close failed")
+	assert.Equal(t, []string{"start db", "start http", "stop http", "stop db"}, calls)
+}
+
+func TestLifecycle_RunStopsOnFailure(t *testing.T) {
+	var calls []string
+	boom := errors.New("listener closed")
+	l := New()
+	l.Append(recordingHook("db", &calls, nil, nil))
+	l.Append(Hook{
+		Name: "http",
+		OnStart: func(ctx context.Context) error {
+			go l.Fail(boom)
+			return nil
+		},
+	})
+
+	err := l.Run(context.Background(), time.Second)
+
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, []string{"start db", "stop db"}, calls)
+}
+
+func TestLifecycle_RunStopsOnContextCancel(t *testing.T) {
+	var calls []string
+	l := New()
+	l.Append(recordingHook("db", &calls, nil, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, l.Run(ctx, time.Second))
+	assert.Equal(t, []string{"start db", "stop db"}, calls)
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestNew_AddsContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithAttrs(ctx, slog.Int("user_id", 7))
+	log.InfoContext(ctx, "purchase completed", "item", "cup")
+
+	entry := decode(t, &buf)
+	assert.Equal(t, "purchase completed", entry["msg"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, 7.0, entry["user_id"])
+	assert.Equal(t, "cup", entry["item"])
+}
+
+func TestNew_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, slog.LevelWarn)
+
+	log.Info("skipped")
+	assert.Empty(t, buf.String())
+
+	log.Warn("kept")
+	assert.Equal(t, "WARN", decode(t, &buf)["level"])
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Empty(t, RequestID(context.Background()))
+
+	ctx := WithRequestID(context.Background(), "outer")
+	ctx = WithAttrs(ctx, slog.Int("user_id", 1))
+	assert.Equal(t, "outer", RequestID(ctx))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel(" DEBUG ")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, level)
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
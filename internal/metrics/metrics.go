@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "merchio"
+
+// Registry - собственный реестр вместо глобального, чтобы в /metrics попадало только наше
+var Registry = prometheus.NewRegistry()
+
+var (
+	logins = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Successful logins, including automatic sign-ups.",
+	})
+	failedLogins = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_logins_total",
+		Help:      "Login attempts rejected because of a wrong password.",
+	})
+	lockouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_lockouts_total",
+		Help:      "Username and IP pairs locked out after repeated failed logins.",
+	})
+	transfers = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coin_transfers_total",
+		Help:      "Completed coin transfers between users.",
+	})
+	coinsMoved = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_moved_total",
+		Help:      "Coins moved by kind: transfer between users or purchase spent on merch.",
+	}, []string{"kind"})
+	purchases = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "purchases_total",
+		Help:      "Completed merch purchases per item.",
+	}, []string{"item"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		logins,
+		failedLogins,
+		lockouts,
+		transfers,
+		coinsMoved,
+		purchases,
+	)
+}
+
+// Handler отдаёт метрики в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+func ObserveLogin(success bool) {
+	if success {
+		logins.Inc()
+	} else {
+		failedLogins.Inc()
+	}
+}
+
+func ObserveLockout() {
+	lockouts.Inc()
+}
+
+func ObserveTransfer(amount int) {
+	transfers.Inc()
+	coinsMoved.WithLabelValues("transfer").Add(float64(amount))
+}
+
+func ObservePurchase(item string, price int) {
+	purchases.WithLabelValues(item).Inc()
+	coinsMoved.WithLabelValues("purchase").Add(float64(price))
+}
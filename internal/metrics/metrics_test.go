@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_LabelsByRoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/buy/{item}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	r.Get("/api/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})
+
+	before := testutil.ToFloat64(httpRequests.WithLabelValues("/api/buy/{item}", http.MethodGet, "409"))
+	for _, path := range []string{"/api/buy/cup", "/api/buy/hoody", "/api/info", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(httpRequests.WithLabelValues("/api/buy/{item}", http.MethodGet, "409")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("/api/info", http.MethodGet, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("unmatched", http.MethodGet, "404")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(httpRequests.WithLabelValues("/api/buy/cup", http.MethodGet, "409")))
+
+	count := testutil.CollectAndCount(httpDuration, namespace+"_http_request_duration_seconds")
+	assert.GreaterOrEqual(t, count, 3)
+}
+
+func TestBusinessCounters(t *testing.T) {
+	loginsBefore := testutil.ToFloat64(logins)
+	failedBefore := testutil.ToFloat64(failedLogins)
+	transfersBefore := testutil.ToFloat64(transfers)
+	movedBefore := testutil.ToFloat64(coinsMoved.WithLabelValues("transfer"))
+	spentBefore := testutil.ToFloat64(coinsMoved.WithLabelValues("purchase"))
+	cupsBefore := testutil.ToFloat64(purchases.WithLabelValues("cup"))
+
+	ObserveLogin(true)
+	ObserveLogin(false)
+	ObserveLogin(false)
+	ObserveTransfer(150)
+	ObservePurchase("cup", 20)
+
+	assert.Equal(t, loginsBefore+1, testutil.ToFloat64(logins))
+	assert.Equal(t, failedBefore+2, testutil.ToFloat64(failedLogins))
+	assert.Equal(t, transfersBefore+1, testutil.ToFloat64(transfers))
+	assert.Equal(t, movedBefore+150, testutil.ToFloat64(coinsMoved.WithLabelValues("transfer")))
+	assert.Equal(t, spentBefore+20, testutil.ToFloat64(coinsMoved.WithLabelValues("purchase")))
+	assert.Equal(t, cupsBefore+1, testutil.ToFloat64(purchases.WithLabelValues("cup")))
+}
+
+func TestPoolCollector(t *testing.T) {
+	// LazyConnect не ходит в БД, статистика пустого пула доступна сразу
+	cfg, err := pgxpool.ParseConfig("postgres://merchio@localhost:5432/merchio?pool_max_conns=7")
+	require.NoError(t, err)
+	cfg.LazyConnect = true
+	pool, err := pgxpool.ConnectConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewPoolCollector(pool))
+
+	expected := `
+# HELP merchio_db_pool_max_conns Maximum size of the pool.
+# TYPE merchio_db_pool_max_conns gauge
+merchio_db_pool_max_conns 7
+# HELP merchio_db_pool_acquired_conns Connections currently checked out of the pool.
+# TYPE merchio_db_pool_acquired_conns gauge
+merchio_db_pool_acquired_conns 0
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"merchio_db_pool_max_conns", "merchio_db_pool_acquired_conns")
+	assert.NoError(t, err)
+	assert.Equal(t, 8, testutil.CollectAndCount(NewPoolCollector(pool)))
+}
+
+func TestHandler_ExposesRegisteredMetrics(t *testing.T) {
+	ObserveLogin(true)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "merchio_logins_total")
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
@@ -4,4 +4,4 @@ CREATE TABLE IF NOT EXISTS users (
     password VARCHAR(255) NOT NULL,
     coins INTEGER DEFAULT 1000,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-    );
\ No newline at end of file
+    );
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS wishlist_items;
+DROP TABLE IF EXISTS merch_items;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS merch_items (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    price INTEGER NOT NULL CHECK (price > 0),
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    category VARCHAR(64) NOT NULL DEFAULT ''
+    );
+
+INSERT INTO merch_items (name, price, stock, category) VALUES
+    ('t-shirt', 80, 100, 'apparel'),
+    ('cup', 20, 100, 'accessories'),
+    ('book', 50, 100, 'stationery'),
+    ('pen', 10, 100, 'stationery'),
+    ('powerbank', 200, 100, 'accessories'),
+    ('hoody', 300, 100, 'apparel'),
+    ('umbrella', 200, 100, 'accessories'),
+    ('socks', 10, 100, 'apparel'),
+    ('wallet', 50, 100, 'accessories'),
+    ('pink-hoody', 500, 100, 'apparel')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    merch_id INTEGER NOT NULL REFERENCES merch_items (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, merch_id)
+    );
+
+CREATE INDEX IF NOT EXISTS wishlist_items_merch_id_idx ON wishlist_items (merch_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    merch_id INTEGER REFERENCES merch_items (id) ON DELETE SET NULL,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_idx ON notifications (user_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_inventory;
+DROP TABLE IF EXISTS merch_variants;
@@ -0,0 +1,42 @@
+-- Для товаров с вариантами остаток ведётся по вариантам, merch_items.stock не используется
+CREATE TABLE IF NOT EXISTS merch_variants (
+    id SERIAL PRIMARY KEY,
+    merch_id INTEGER NOT NULL REFERENCES merch_items (id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    size VARCHAR(16) NOT NULL DEFAULT '',
+    color VARCHAR(32) NOT NULL DEFAULT '',
+    price_override INTEGER CHECK (price_override > 0),
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0)
+    );
+
+CREATE INDEX IF NOT EXISTS merch_variants_merch_id_idx ON merch_variants (merch_id);
+
+INSERT INTO merch_variants (merch_id, sku, size, color, price_override, stock)
+SELECT m.id, v.sku, v.size, v.color, v.price_override, v.stock
+FROM (VALUES
+    ('t-shirt', 'TSHIRT-BLK-S', 'S', 'black', NULL, 25),
+    ('t-shirt', 'TSHIRT-BLK-M', 'M', 'black', NULL, 25),
+    ('t-shirt', 'TSHIRT-BLK-L', 'L', 'black', NULL, 25),
+    ('t-shirt', 'TSHIRT-BLK-XL', 'XL', 'black', NULL, 25),
+    ('hoody', 'HOODY-GRY-S', 'S', 'grey', NULL, 25),
+    ('hoody', 'HOODY-GRY-M', 'M', 'grey', NULL, 25),
+    ('hoody', 'HOODY-GRY-L', 'L', 'grey', NULL, 25),
+    ('hoody', 'HOODY-GRY-XL', 'XL', 'grey', 320, 25),
+    ('pink-hoody', 'PHOODY-PNK-S', 'S', 'pink', NULL, 10),
+    ('pink-hoody', 'PHOODY-PNK-M', 'M', 'pink', NULL, 10),
+    ('pink-hoody', 'PHOODY-PNK-L', 'L', 'pink', NULL, 10)
+) AS v (item, sku, size, color, price_override, stock)
+JOIN merch_items m ON m.name = v.item
+ON CONFLICT (sku) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS user_inventory (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    merch_id INTEGER NOT NULL REFERENCES merch_items (id),
+    variant_id INTEGER REFERENCES merch_variants (id),
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_inventory_item_idx
+    ON user_inventory (user_id, merch_id, (COALESCE(variant_id, 0)));
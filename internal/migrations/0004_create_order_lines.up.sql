@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS order_lines (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- Для подарка: товар попадает в инвентарь получателя, монеты списываются с user_id
+    recipient_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    merch_id INTEGER NOT NULL REFERENCES merch_items (id),
+    variant_id INTEGER REFERENCES merch_variants (id),
+    price INTEGER NOT NULL,
+    discount INTEGER NOT NULL DEFAULT 0,
+    promotion_id INTEGER,
+    coupon_code VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT 'placed'
+        CHECK (status IN ('placed', 'packed', 'ready_for_pickup', 'delivered')),
+    pickup_location VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS order_lines_user_id_idx ON order_lines (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS order_lines_recipient_id_idx ON order_lines (recipient_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS order_lines_status_idx ON order_lines (status);
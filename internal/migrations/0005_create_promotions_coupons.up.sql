@@ -0,0 +1,40 @@
+-- Скидка действует на конкретный товар или на всю категорию
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    merch_id INTEGER REFERENCES merch_items (id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (merch_id IS NOT NULL OR category <> ''),
+    CHECK (ends_at > starts_at)
+    );
+
+CREATE INDEX IF NOT EXISTS promotions_merch_id_idx ON promotions (merch_id);
+CREATE INDEX IF NOT EXISTS promotions_category_idx ON promotions (category);
+
+-- max_uses = NULL - без ограничения общего числа использований, один пользователь использует купон один раз
+CREATE TABLE IF NOT EXISTS coupons (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    merch_id INTEGER REFERENCES merch_items (id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    max_uses INTEGER CHECK (max_uses > 0),
+    used_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    coupon_id INTEGER NOT NULL REFERENCES coupons (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    order_line_id INTEGER NOT NULL REFERENCES order_lines (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (coupon_id, user_id)
+    );
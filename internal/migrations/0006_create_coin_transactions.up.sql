@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS coin_transactions (
+    id SERIAL PRIMARY KEY,
+    from_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    to_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_id <> to_id)
+    );
+
+CREATE INDEX IF NOT EXISTS coin_transactions_from_id_idx ON coin_transactions (from_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS coin_transactions_to_id_idx ON coin_transactions (to_id, created_at DESC);
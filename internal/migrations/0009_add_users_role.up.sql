@@ -0,0 +1,3 @@
+-- Колонка добавляется отдельно: на базах, созданных до миграций, таблица users уже есть,
+-- и CREATE TABLE IF NOT EXISTS из 0001 её не меняет
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'user';
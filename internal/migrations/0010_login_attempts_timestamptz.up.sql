@@ -0,0 +1,6 @@
+-- Блокировка сравнивается с текущим моментом, поэтому хранится как момент времени, а не
+-- как показание часов: TIMESTAMP терял пояс приложения, и при поясе, отличном от UTC,
+-- блокировка снималась раньше или позже срока. Старые значения читаются в поясе сессии.
+ALTER TABLE login_attempts
+    ALTER COLUMN last_failure_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN locked_until TYPE TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+ALTER TABLE coupons
+    ALTER COLUMN starts_at TYPE TIMESTAMP,
+    ALTER COLUMN ends_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE promotions
+    ALTER COLUMN starts_at TYPE TIMESTAMP,
+    ALTER COLUMN ends_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP;
@@ -0,0 +1,11 @@
+-- Сроки акций и купонов сравниваются с now() базы, поэтому хранятся как моменты времени.
+-- Старые значения читаются в поясе сессии: в нём их и сравнивал CURRENT_TIMESTAMP.
+ALTER TABLE promotions
+    ALTER COLUMN starts_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN ends_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE coupons
+    ALTER COLUMN starts_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN ends_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
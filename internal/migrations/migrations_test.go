@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_items.up.sql":   {Data: []byte("CREATE TABLE items ();")},
+		"0002_add_items.down.sql": {Data: []byte("DROP TABLE items;")},
+		"0001_init.up.sql":        {Data: []byte("CREATE TABLE users ();")},
+		"0001_init.down.sql":      {Data: []byte("DROP TABLE users;")},
+		"readme.md":               {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys)
+
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "CREATE TABLE users ();", migrations[0].Up)
+	assert.Equal(t, "DROP TABLE items;", migrations[1].Down)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "Нет down файла",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+		{
+			name: "Разные имена у одной версии",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+				"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(Files)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, "версии миграций должны идти без пропусков")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+
+	versions := func(ms []Migration) []int64 {
+		var vs []int64
+		for _, m := range ms {
+			vs = append(vs, m.Version)
+		}
+		return vs
+	}
+
+	tests := []struct {
+		name         string
+		applied      map[int64]bool
+		target       int64
+		expectedUp   []int64
+		expectedDown []int64
+	}{
+		{
+			name:       "Чистая база",
+			applied:    map[int64]bool{},
+			target:     4,
+			expectedUp: []int64{1, 2, 3, 4},
+		},
+		{
+			name:       "Часть уже применена",
+			applied:    map[int64]bool{1: true, 2: true},
+			target:     4,
+			expectedUp: []int64{3, 4},
+		},
+		{
+			name:         "Откат до версии",
+			applied:      map[int64]bool{1: true, 2: true, 3: true, 4: true},
+			target:       2,
+			expectedDown: []int64{4, 3},
+		},
+		{
+			name:         "Откат всего",
+			applied:      map[int64]bool{1: true, 2: true},
+			target:       0,
+			expectedDown: []int64{2, 1},
+		},
+		{
+			name:    "Ничего делать не нужно",
+			applied: map[int64]bool{1: true, 2: true, 3: true},
+			target:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up, down := plan(migrations, tt.applied, tt.target)
+			assert.Equal(t, tt.expectedUp, versions(up))
+			assert.Equal(t, tt.expectedDown, versions(down))
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// lockKey - ключ pg_advisory_lock, чтобы несколько реплик не накатывали миграции одновременно
+const lockKey int64 = 0x6d65726368696f
+
+const createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// Up накатывает все неприменённые миграции
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.To(ctx, latest(m.migrations))
+}
+
+// Down откатывает steps последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var applied []int64
+	for _, s := range statuses {
+		if s.Applied {
+			applied = append(applied, s.Version)
+		}
+	}
+	if steps <= 0 || len(applied) == 0 {
+		return 0, nil
+	}
+
+	var target int64
+	if steps < len(applied) {
+		target = applied[len(applied)-steps-1]
+	}
+	return m.To(ctx, target)
+}
+
+// To приводит схему к версии target, накатывая или откатывая миграции
+func (m *Migrator) To(ctx context.Context, target int64) (int, error) {
+	if target < 0 || (target > 0 && !m.known(target)) {
+		return 0, errors.Errorf("unknown migration version %d", target)
+	}
+
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer m.unlock(conn)
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	up, down := plan(m.migrations, applied, target)
+	for _, mig := range down {
+		err := run(ctx, conn, mig.Down, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+		if err != nil {
+			return 0, errors.Wrapf(err, "migration %d_%s down", mig.Version, mig.Name)
+		}
+	}
+	for _, mig := range up {
+		err := run(ctx, conn, mig.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name)
+		if err != nil {
+			return 0, errors.Wrapf(err, "migration %d_%s up", mig.Version, mig.Name)
+		}
+	}
+	return len(up) + len(down), nil
+}
+
+// Status возвращает все известные миграции с отметкой, применена ли каждая.
+// Только читает: его вызывает readiness, а у роли сервиса может не быть прав на DDL.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	appliedAt, err := m.appliedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := appliedAt[mig.Version]; ok {
+			s.Applied, s.AppliedAt = true, &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// appliedAt - время применения по версиям. Таблицы версий ещё нет - не применена ни одна миграция.
+func (m *Migrator) appliedAt(ctx context.Context) (map[int64]time.Time, error) {
+	appliedAt := make(map[int64]time.Time)
+	var exists bool
+	if err := m.db.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return appliedAt, nil
+	}
+
+	rows, err := m.db.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	return appliedAt, rows.Err()
+}
+
+// Pending - количество неприменённых миграций
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending int
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// lock берёт отдельное соединение: advisory lock держится на уровне сессии
+func (m *Migrator) lock(ctx context.Context) (*pgxpool.Conn, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, createVersionTable); err != nil {
+		m.unlock(conn)
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (m *Migrator) unlock(conn *pgxpool.Conn) {
+	// Контекст запроса мог быть отменён, а блокировку нужно снять в любом случае
+	conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+	conn.Release()
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]bool, error) {
+	rows, err := conn.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// run выполняет миграцию и запись о ней в schema_migrations одной транзакцией
+func run(ctx context.Context, conn *pgxpool.Conn, sql, record string, args ...interface{}) error {
+	return conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, record, args...)
+		return err
+	})
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// LoginAttempts - неудачные входы с одного IP под одним именем
+type LoginAttempts struct {
+	Username      string     `json:"username"`
+	IP            string     `json:"ip"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+// Locked - действует ли блокировка на момент now
+func (a LoginAttempts) Locked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+const (
+	AuditLoginLocked   = "login.locked"
+	AuditLoginUnlocked = "login.unlocked"
+)
+
+// AuditEntry - запись журнала действий, важных для безопасности
+type AuditEntry struct {
+	ID     uint   `json:"id"`
+	Action string `json:"action"`
+	// ActorID - кто выполнил действие, nil для действий системы
+	ActorID   *int      `json:"actor_id,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	AuditPasswordChanged     = "password.changed"
+	AuditPasswordResetIssued = "password.reset_issued"
+	AuditPasswordReset       = "password.reset"
+)
+
+// PasswordResetToken - одноразовый токен сброса пароля. В базе хранится только TokenHash,
+// сам Token отдаётся администратору один раз при выдаче.
+type PasswordResetToken struct {
+	Token     string    `json:"token"`
+	TokenHash string    `json:"-"`
+	UserID    int       `json:"user_id"`
+	CreatedBy int       `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
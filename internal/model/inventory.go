@@ -0,0 +1,41 @@
+package model
+
+// InventoryItem - строка инвентаря для /api/info
+type InventoryItem struct {
+	Type     string `json:"type"`
+	Quantity int    `json:"quantity"`
+	SKU      string `json:"sku,omitempty"`
+	Size     string `json:"size,omitempty"`
+	Color    string `json:"color,omitempty"`
+}
+
+type UserInfo struct {
+	Coins       int             `json:"coins"`
+	Inventory   []InventoryItem `json:"inventory"`
+	CoinHistory CoinHistory     `json:"coinHistory"`
+}
+
+type PurchaseRequest struct {
+	UserID uint
+	Item   string
+	SKU    string
+	Coupon string
+	// Recipient - имя пользователя, которому дарят товар, пусто для покупки себе
+	Recipient string
+}
+
+type Purchase struct {
+	OrderID     uint   `json:"order_id"`
+	UserID      uint   `json:"user_id"`
+	RecipientID *uint  `json:"recipient_id,omitempty"`
+	Recipient   string `json:"recipient,omitempty"`
+	Item        string `json:"item"`
+	MerchID     uint   `json:"-"`
+	SKU         string `json:"sku,omitempty"`
+	ListPrice   int    `json:"list_price"`
+	Discount    int    `json:"discount"`
+	PromotionID *uint  `json:"promotion_id,omitempty"`
+	Coupon      string `json:"coupon,omitempty"`
+	Price       int    `json:"price"`
+	Balance     int    `json:"balance"`
+}
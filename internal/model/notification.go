@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	NotificationPriceDrop   = "price_drop"
+	NotificationBackInStock = "back_in_stock"
+	NotificationOrderReady  = "order_ready"
+	NotificationGift        = "gift"
+)
+
+type Notification struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Kind      string     `json:"kind"`
+	MerchID   uint       `json:"merch_id"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	FulfilmentPlaced         = "placed"
+	FulfilmentPacked         = "packed"
+	FulfilmentReadyForPickup = "ready_for_pickup"
+	FulfilmentDelivered      = "delivered"
+)
+
+// fulfilmentFlow - порядок, в котором строка заказа проходит выдачу
+var fulfilmentFlow = []string{
+	FulfilmentPlaced,
+	FulfilmentPacked,
+	FulfilmentReadyForPickup,
+	FulfilmentDelivered,
+}
+
+// NextFulfilmentStatus возвращает следующий статус, false если статус последний или неизвестен
+func NextFulfilmentStatus(status string) (string, bool) {
+	for i, s := range fulfilmentFlow {
+		if s == status && i+1 < len(fulfilmentFlow) {
+			return fulfilmentFlow[i+1], true
+		}
+	}
+	return "", false
+}
+
+func IsFulfilmentStatus(status string) bool {
+	for _, s := range fulfilmentFlow {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type OrderLine struct {
+	ID             uint      `json:"id"`
+	UserID         uint      `json:"user_id"`
+	Buyer          string    `json:"buyer"`
+	RecipientID    *uint     `json:"recipient_id,omitempty"`
+	Recipient      string    `json:"recipient,omitempty"`
+	MerchID        uint      `json:"merch_id"`
+	Item           string    `json:"item"`
+	SKU            string    `json:"sku,omitempty"`
+	Price          int       `json:"price"`
+	Discount       int       `json:"discount"`
+	PromotionID    *uint     `json:"promotion_id,omitempty"`
+	CouponCode     string    `json:"coupon_code,omitempty"`
+	Status         string    `json:"status"`
+	PickupLocation string    `json:"pickup_location,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (l OrderLine) IsGift() bool {
+	return l.RecipientID != nil && *l.RecipientID != l.UserID
+}
+
+// OwnerID - кто получает товар: получатель подарка или сам покупатель
+func (l OrderLine) OwnerID() uint {
+	if l.RecipientID != nil {
+		return *l.RecipientID
+	}
+	return l.UserID
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+type Discount struct {
+	Kind  string `json:"kind"`
+	Value int    `json:"value"`
+}
+
+// Amount - размер скидки в монетах, не больше самой цены
+func (d Discount) Amount(price int) int {
+	var amount int
+	switch d.Kind {
+	case DiscountPercent:
+		amount = price * d.Value / 100
+	case DiscountFixed:
+		amount = d.Value
+	}
+	if amount > price {
+		return price
+	}
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
+
+func (d Discount) Valid() bool {
+	switch d.Kind {
+	case DiscountPercent:
+		return d.Value > 0 && d.Value <= 100
+	case DiscountFixed:
+		return d.Value > 0
+	}
+	return false
+}
+
+// Promotion - ограниченная по времени скидка на товар или категорию
+type Promotion struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	MerchID  *uint  `json:"merch_id,omitempty"`
+	Category string `json:"category,omitempty"`
+	Discount
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Coupon struct {
+	ID       uint   `json:"id"`
+	Code     string `json:"code"`
+	MerchID  *uint  `json:"merch_id,omitempty"`
+	Category string `json:"category,omitempty"`
+	Discount
+	MaxUses   *int       `json:"max_uses,omitempty"`
+	UsedCount int        `json:"used_count"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (c Coupon) Active(now time.Time) bool {
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return false
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return false
+	}
+	return true
+}
+
+func (c Coupon) Exhausted() bool {
+	return c.MaxUses != nil && c.UsedCount >= *c.MaxUses
+}
+
+// AppliesTo - купон без товара и категории действует на всё
+func (c Coupon) AppliesTo(merchID uint, category string) bool {
+	if c.MerchID != nil {
+		return *c.MerchID == merchID
+	}
+	if c.Category != "" {
+		return c.Category == category
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscount_Amount(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		price    int
+		expected int
+	}{
+		{name: "Процент", discount: Discount{Kind: DiscountPercent, Value: 20}, price: 500, expected: 100},
+		{name: "Процент округляется вниз", discount: Discount{Kind: DiscountPercent, Value: 15}, price: 10, expected: 1},
+		{name: "Фиксированная", discount: Discount{Kind: DiscountFixed, Value: 30}, price: 80, expected: 30},
+		{name: "Не больше цены", discount: Discount{Kind: DiscountFixed, Value: 30}, price: 20, expected: 20},
+		{name: "Неизвестный вид", discount: Discount{Kind: "bogus", Value: 30}, price: 20, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.discount.Amount(tt.price))
+		})
+	}
+}
+
+func TestCoupon_Rules(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	one := 1
+	merchID := uint(6)
+
+	assert.True(t, Coupon{}.Active(now))
+	assert.False(t, Coupon{StartsAt: &future}.Active(now))
+	assert.False(t, Coupon{EndsAt: &past}.Active(now))
+
+	assert.False(t, Coupon{}.Exhausted())
+	assert.True(t, Coupon{MaxUses: &one, UsedCount: 1}.Exhausted())
+
+	assert.True(t, Coupon{}.AppliesTo(2, "accessories"))
+	assert.True(t, Coupon{MerchID: &merchID}.AppliesTo(6, "apparel"))
+	assert.False(t, Coupon{MerchID: &merchID}.AppliesTo(2, "accessories"))
+	assert.False(t, Coupon{Category: "apparel"}.AppliesTo(2, "accessories"))
+}
@@ -0,0 +1,17 @@
+package model
+
+// CoinHistory - история переводов для /api/info
+type CoinHistory struct {
+	Received []ReceivedCoins `json:"received"`
+	Sent     []SentCoins     `json:"sent"`
+}
+
+type ReceivedCoins struct {
+	FromUser string `json:"fromUser"`
+	Amount   int    `json:"amount"`
+}
+
+type SentCoins struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+}
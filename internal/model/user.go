@@ -9,19 +9,50 @@ type User struct {
 	Username  string    `json:"username"`
 	Password  string    `json:"-"`
 	Coins     int       `json:"coins"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type MerchItem struct {
 	ID    uint   `json:"id"`
 	Name  string `json:"name"`
 	Price int    `json:"price"`
+	Stock int    `json:"stock"`
+
+	Category string         `json:"category,omitempty"`
+	Variants []MerchVariant `json:"variants,omitempty"`
+	// HasVariants - товар продаётся из остатков вариантов, а не из Stock
+	HasVariants bool `json:"-"`
+}
+
+type MerchVariant struct {
+	ID            uint   `json:"id"`
+	MerchID       uint   `json:"merch_id"`
+	SKU           string `json:"sku"`
+	Size          string `json:"size,omitempty"`
+	Color         string `json:"color,omitempty"`
+	PriceOverride *int   `json:"price_override,omitempty"`
+	Stock         int    `json:"stock"`
+}
+
+// Price - цена варианта с учётом переопределения
+func (v MerchVariant) Price(itemPrice int) int {
+	if v.PriceOverride != nil {
+		return *v.PriceOverride
+	}
+	return itemPrice
 }
 
 type UserInventory struct {
 	ID        uint      `json:"id"`
 	UserID    uint      `json:"user_id"`
 	MerchID   uint      `json:"merch_id"`
+	VariantID *uint     `json:"variant_id,omitempty"`
 	Quantity  int       `json:"quantity"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+type WishlistItem struct {
+	UserID    uint      `json:"user_id"`
+	MerchID   uint      `json:"merch_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WishlistEntry - товар из списка желаний вместе с тем, сколько монет на него не хватает
+type WishlistEntry struct {
+	Item        MerchItem `json:"item"`
+	CoinsNeeded int       `json:"coins_needed"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+type Wishlist struct {
+	Coins int             `json:"coins"`
+	Items []WishlistEntry `json:"items"`
+}
@@ -0,0 +1,82 @@
+// Package passhash - хеширование паролей. Хеши самоописывающие: по префиксу видно алгоритм и параметры
+// ($2a$10$... для bcrypt, $argon2id$v=19$m=65536,t=3,p=2$... в формате PHC для Argon2id),
+// поэтому в одной таблице могут жить хеши разных алгоритмов.
+package passhash
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMismatch - пароль не совпадает с хешем
+	ErrMismatch = errors.New("password does not match")
+	// ErrUnknownFormat - хеш не распознан ни одним алгоритмом
+	ErrUnknownFormat = errors.New("unknown password hash format")
+)
+
+// Hasher считает и проверяет хеши паролей
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Verify возвращает ErrMismatch, если пароль не подходит
+	Verify(encoded, password string) error
+	// Identifies - хеш закодирован этим алгоритмом
+	Identifies(encoded string) bool
+	// NeedsRehash - хеш стоит пересчитать: другой алгоритм или устаревшие параметры
+	NeedsRehash(encoded string) bool
+}
+
+// Set хеширует основным алгоритмом, а проверяет любым из известных
+type Set struct {
+	primary Hasher
+	all     []Hasher
+}
+
+// New - primary для новых хешей, legacy - алгоритмы, хеши которых ещё встречаются в базе
+func New(primary Hasher, legacy ...Hasher) *Set {
+	return &Set{primary: primary, all: append([]Hasher{primary}, legacy...)}
+}
+
+func (s *Set) Hash(password string) (string, error) {
+	return s.primary.Hash(password)
+}
+
+func (s *Set) Verify(encoded, password string) error {
+	for _, h := range s.all {
+		if h.Identifies(encoded) {
+			return h.Verify(encoded, password)
+		}
+	}
+	return ErrUnknownFormat
+}
+
+func (s *Set) Identifies(encoded string) bool {
+	for _, h := range s.all {
+		if h.Identifies(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsRehash - хеш чужого алгоритма или основного, но с устаревшими параметрами
+func (s *Set) NeedsRehash(encoded string) bool {
+	return !s.primary.Identifies(encoded) || s.primary.NeedsRehash(encoded)
+}
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// ForAlgorithm - новые хеши считает algorithm, проверяются хеши обоих алгоритмов
+func ForAlgorithm(algorithm string, bcryptHasher Bcrypt, argon Argon2id) (*Set, error) {
+	switch algorithm {
+	case AlgorithmArgon2id:
+		return New(argon, bcryptHasher), nil
+	case AlgorithmBcrypt:
+		return New(bcryptHasher, argon), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", algorithm)
+	}
+}
@@ -0,0 +1,128 @@
+package passhash
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Параметры поменьше, чтобы тесты не тратили 64 МиБ на каждый хеш
+var testArgon = Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestHashers_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher Hasher
+		prefix string
+	}{
+		{name: "bcrypt", hasher: Bcrypt{Cost: bcrypt.MinCost}, prefix: "$2a$04$"},
+		{name: "argon2id", hasher: testArgon, prefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.hasher.Hash("password123")
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(encoded, tt.prefix), encoded)
+			assert.True(t, tt.hasher.Identifies(encoded))
+			assert.NoError(t, tt.hasher.Verify(encoded, "password123"))
+			assert.ErrorIs(t, tt.hasher.Verify(encoded, "password124"), ErrMismatch)
+			assert.False(t, tt.hasher.NeedsRehash(encoded))
+
+			again, _ := tt.hasher.Hash("password123")
+			assert.NotEqual(t, encoded, again, "salt must differ")
+		})
+	}
+}
+
+func TestArgon2id_NeedsRehash(t *testing.T) {
+	encoded, err := testArgon.Hash("password123")
+	require.NoError(t, err)
+
+	// Хеш проверяется по своим параметрам, даже если настройки уже другие
+	stronger := Argon2id{Memory: 2048, Iterations: 2, Parallelism: 1}
+	assert.NoError(t, stronger.Verify(encoded, "password123"))
+	assert.True(t, stronger.NeedsRehash(encoded))
+	assert.False(t, testArgon.NeedsRehash(encoded))
+}
+
+func TestArgon2id_RejectsMalformed(t *testing.T) {
+	for _, encoded := range []string{
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$aGFzaA",
+	} {
+		err := testArgon.Verify(encoded, "password123")
+		assert.Error(t, err, encoded)
+		assert.NotErrorIs(t, err, ErrMismatch, encoded)
+	}
+}
+
+func TestArgon2id_LimitsConcurrency(t *testing.T) {
+	prev := argon2Slots
+	argon2Slots = make(chan struct{}, 1)
+	defer func() { argon2Slots = prev }()
+
+	// Единственный слот занят, хеширование должно ждать его освобождения
+	argon2Slots <- struct{}{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := testArgon.Hash("passw0rd")
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("hash computed while no slot was free")
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-argon2Slots
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hash did not start after the slot was freed")
+	}
+}
+
+func TestSet_MigratesLegacyHashes(t *testing.T) {
+	set := New(testArgon, Bcrypt{Cost: bcrypt.MinCost})
+	legacy, _ := Bcrypt{Cost: bcrypt.MinCost}.Hash("password123")
+
+	// Старый bcrypt-хеш проверяется, но помечается к пересчёту
+	assert.NoError(t, set.Verify(legacy, "password123"))
+	assert.True(t, set.NeedsRehash(legacy))
+
+	fresh, err := set.Hash("password123")
+	require.NoError(t, err)
+	assert.True(t, testArgon.Identifies(fresh))
+	assert.NoError(t, set.Verify(fresh, "password123"))
+	assert.False(t, set.NeedsRehash(fresh))
+
+	assert.ErrorIs(t, set.Verify("plaintext", "plaintext"), ErrUnknownFormat)
+}
+
+func TestBcrypt_NeedsRehash(t *testing.T) {
+	cheap, _ := Bcrypt{Cost: bcrypt.MinCost}.Hash("password123")
+	expensive, _ := Bcrypt{Cost: bcrypt.MinCost + 2}.Hash("password123")
+	h := Bcrypt{Cost: bcrypt.MinCost + 1}
+
+	assert.True(t, h.NeedsRehash(cheap))
+	assert.False(t, h.NeedsRehash(expensive))
+}
+
+func TestForAlgorithm(t *testing.T) {
+	set, err := ForAlgorithm(AlgorithmBcrypt, Bcrypt{Cost: bcrypt.MinCost}, testArgon)
+	require.NoError(t, err)
+	encoded, _ := set.Hash("password123")
+	assert.True(t, strings.HasPrefix(encoded, "$2a$"))
+
+	_, err = ForAlgorithm("scrypt", Bcrypt{}, testArgon)
+	assert.Error(t, err)
+}
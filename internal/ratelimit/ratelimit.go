@@ -0,0 +1,111 @@
+// Package ratelimit - ограничение частоты запросов по алгоритму token bucket.
+// Корзины хранятся в Store: в памяти процесса (MemoryStore) или в общем хранилище для нескольких реплик.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit - Burst токенов в корзине, пополняется со скоростью Rate токенов в секунду.
+// Нулевой Limit означает отсутствие ограничения.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Every - n запросов за period, всплеск до n подряд
+func Every(n int, period time.Duration) Limit {
+	return Limit{Rate: float64(n) / period.Seconds(), Burst: n}
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, time.Duration(float64(l.Burst)/l.Rate*float64(time.Second)))
+}
+
+// ParseLimit разбирает запись вида "10/1m" - 10 запросов в минуту. "0" или "off" отключают ограничение.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" || s == "off" {
+		return Limit{}, nil
+	}
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("expected a limit like 10/1m, got %q", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("limit count must be a positive integer, got %q", count)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit period must be a positive duration, got %q", period)
+	}
+	return Every(n, d), nil
+}
+
+// ParseRoutes разбирает переопределения для маршрутов:
+// "POST /api/sendCoin=5/1s, GET /api/buy/{item}=10/1s"
+func ParseRoutes(s string) (map[string]Limit, error) {
+	routes := make(map[string]Limit)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, raw, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected ROUTE=LIMIT, got %q", entry)
+		}
+		limit, err := ParseLimit(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.TrimSpace(route), err)
+		}
+		routes[strings.TrimSpace(route)] = limit
+	}
+	return routes, nil
+}
+
+// Policy выбирает лимит для запроса: по маршруту, если он переопределён, иначе по типу клиента
+type Policy struct {
+	// Anonymous - для запросов без пользователя, ключ - IP
+	Anonymous Limit
+	// User - для авторизованных запросов, ключ - user_id
+	User Limit
+	// Routes - переопределения по "МЕТОД /шаблон маршрута chi"
+	Routes map[string]Limit
+}
+
+func (p Policy) For(route string, authenticated bool) Limit {
+	if limit, ok := p.Routes[route]; ok {
+		return limit
+	}
+	if authenticated {
+		return p.User
+	}
+	return p.Anonymous
+}
+
+// Result - решение по одному запросу
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter - через сколько появится следующий токен, если запрос отклонён
+	RetryAfter time.Duration
+}
+
+// Store хранит корзины. Take атомарно пополняет корзину key и забирает из неё токен.
+// Общее хранилище (например, Redis) реализует этот же интерфейс.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
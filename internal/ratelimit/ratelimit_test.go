@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock - управляемое время для MemoryStore
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store, clock := newTestStore()
+	ctx := context.Background()
+	limit := Every(3, time.Minute)
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, _ := store.Take(ctx, "ip:10.0.0.1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 20*time.Second, result.RetryAfter)
+
+	// Другой ключ считается отдельно
+	result, _ = store.Take(ctx, "ip:10.0.0.2", limit)
+	assert.True(t, result.Allowed)
+
+	// За 20 секунд набегает один токен
+	clock.Advance(20 * time.Second)
+	result, _ = store.Take(ctx, "ip:10.0.0.1", limit)
+	assert.True(t, result.Allowed)
+	result, _ = store.Take(ctx, "ip:10.0.0.1", limit)
+	assert.False(t, result.Allowed)
+}
+
+func TestMemoryStore_Unlimited(t *testing.T) {
+	store, _ := newTestStore()
+
+	for i := 0; i < 100; i++ {
+		result, err := store.Take(context.Background(), "user:1", Limit{})
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	assert.Zero(t, store.Len())
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	store, clock := newTestStore()
+	ctx := context.Background()
+
+	store.Take(ctx, "ip:10.0.0.1", Every(10, time.Second))
+	store.Take(ctx, "ip:10.0.0.2", Every(10, time.Hour))
+	assert.Equal(t, 2, store.Len())
+
+	// Первая корзина успела наполниться, вторая ещё нет
+	clock.Advance(2 * sweepInterval)
+	store.Take(ctx, "ip:10.0.0.3", Every(10, time.Second))
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected Limit
+		wantErr  bool
+	}{
+		{name: "В минуту", raw: "10/1m", expected: Limit{Rate: 10.0 / 60, Burst: 10}},
+		{name: "В секунду", raw: " 20/1s ", expected: Limit{Rate: 20, Burst: 20}},
+		{name: "Выключено", raw: "off", expected: Limit{}},
+		{name: "Без периода", raw: "10", wantErr: true},
+		{name: "Отрицательное число", raw: "-1/1s", wantErr: true},
+		{name: "Неверный период", raw: "10/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := ParseLimit(tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected.Rate, limit.Rate, 1e-9)
+			assert.Equal(t, tt.expected.Burst, limit.Burst)
+		})
+	}
+}
+
+func TestPolicy_For(t *testing.T) {
+	routes, err := ParseRoutes("POST /api/auth=5/1m, GET /api/buy/{item}=off")
+	require.NoError(t, err)
+	policy := Policy{Anonymous: Every(10, time.Minute), User: Every(20, time.Second), Routes: routes}
+
+	assert.Equal(t, 5, policy.For("POST /api/auth", false).Burst)
+	assert.True(t, policy.For("GET /api/buy/{item}", true).Unlimited())
+	assert.Equal(t, 20, policy.For("GET /api/info", true).Burst)
+	assert.Equal(t, 10, policy.For("GET /api/openapi.json", false).Burst)
+}
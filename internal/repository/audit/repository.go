@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"context"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.AuditRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) Record(ctx context.Context, entry model.AuditEntry) error {
+	_, err := pg.Conn(ctx, r.db).Exec(ctx,
+		"INSERT INTO audit_log (action, actor_id, username, ip, details) VALUES ($1, $2, $3, $4, $5)",
+		entry.Action, entry.ActorID, entry.Username, entry.IP, entry.Details)
+	return err
+}
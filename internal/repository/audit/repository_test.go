@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+)
+
+func TestRepo_Record(t *testing.T) {
+	adminID := 42
+
+	tests := []struct {
+		name    string
+		entry   model.AuditEntry
+		actorID *int
+		dbErr   error
+	}{
+		{
+			name:    "Действие администратора",
+			entry:   model.AuditEntry{Action: model.AuditLoginUnlocked, ActorID: &adminID, Username: "alice", Details: "2 login records cleared"},
+			actorID: &adminID,
+		},
+		{
+			// Действие системы пишется с actor_id = NULL
+			name:  "Действие системы",
+			entry: model.AuditEntry{Action: model.AuditLoginLocked, Username: "alice", IP: "10.0.0.1"},
+		},
+		{
+			name:  "Ошибка БД",
+			entry: model.AuditEntry{Action: model.AuditLoginLocked, Username: "alice"},
+			dbErr: errors.New("ошибка БД"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			exec := mock.ExpectExec("INSERT INTO audit_log").
+				WithArgs(tt.entry.Action, tt.actorID, tt.entry.Username, tt.entry.IP, tt.entry.Details)
+			if tt.dbErr != nil {
+				exec.WillReturnError(tt.dbErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			err = NewRepository(mock).Record(context.Background(), tt.entry)
+
+			assert.ErrorIs(t, err, tt.dbErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package inventory
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"time"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.InventoryRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) BuyMerch(ctx context.Context, req model.PurchaseRequest) (*model.Purchase, error) {
+	var purchase *model.Purchase
+	err := pg.WithTx(ctx, r.db, func(tx pgx.Tx) (err error) {
+		purchase, err = buyMerch(ctx, tx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purchase, nil
+}
+
+// buyMerch - покупка внутри транзакции: списание монет, склад, строка заказа и купон
+func buyMerch(ctx context.Context, tx pgx.Tx, req model.PurchaseRequest) (_ *model.Purchase, err error) {
+	// Получаем информацию о товаре
+	var merchID uint
+	var category string
+	var hasVariants bool
+	err = tx.QueryRow(ctx,
+		`SELECT id, category, EXISTS (SELECT 1 FROM merch_variants v WHERE v.merch_id = m.id)
+         FROM merch_items m WHERE name = $1`,
+		req.Item).Scan(&merchID, &category, &hasVariants)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Блокируем строку, с которой списывается остаток: вариант или сам товар
+	var variantID *uint
+	var listPrice, stock int
+	switch {
+	case req.SKU != "":
+		var id uint
+		err = tx.QueryRow(ctx,
+			`SELECT v.id, COALESCE(v.price_override, m.price), v.stock
+             FROM merch_variants v JOIN merch_items m ON m.id = v.merch_id
+             WHERE v.merch_id = $1 AND v.sku = $2
+             FOR UPDATE OF v`,
+			merchID, req.SKU).Scan(&id, &listPrice, &stock)
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrVariantNotFound
+		}
+		variantID = &id
+	case hasVariants:
+		return nil, repository.ErrVariantRequired
+	default:
+		err = tx.QueryRow(ctx,
+			"SELECT price, stock FROM merch_items WHERE id = $1 FOR UPDATE",
+			merchID).Scan(&listPrice, &stock)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stock < 1 {
+		return nil, repository.ErrOutOfStock
+	}
+	slog.DebugContext(ctx, "merch row locked", "merch_id", merchID, "variant_id", variantID, "list_price", listPrice, "stock", stock)
+
+	promotionID, promoDiscount, err := bestPromotion(ctx, tx, merchID, category, listPrice)
+	if err != nil {
+		return nil, err
+	}
+	price := listPrice - promoDiscount
+	if promotionID != nil {
+		slog.DebugContext(ctx, "promotion applied", "promotion_id", *promotionID, "discount", promoDiscount)
+	}
+
+	// Купон применяется к цене после акции
+	var coupon *model.Coupon
+	if req.Coupon != "" {
+		coupon, err = lockCoupon(ctx, tx, req.Coupon, req.UserID, merchID, category)
+		if err != nil {
+			return nil, err
+		}
+		price -= coupon.Amount(price)
+		slog.DebugContext(ctx, "coupon applied", "coupon", coupon.Code, "price", price)
+	}
+
+	// Получатель подарка, по умолчанию товар достаётся самому покупателю
+	ownerID := req.UserID
+	var recipientID *uint
+	if req.Recipient != "" {
+		var id uint
+		err = tx.QueryRow(ctx, "SELECT id FROM users WHERE username = $1", req.Recipient).Scan(&id)
+		if err == pgx.ErrNoRows {
+			return nil, repository.ErrRecipientNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Подарок самому себе - обычная покупка
+		if id != req.UserID {
+			ownerID, recipientID = id, &id
+		}
+	}
+
+	// Проверяем баланс пользователя
+	var userCoins int
+	err = tx.QueryRow(ctx,
+		"SELECT coins FROM users WHERE id = $1 FOR UPDATE",
+		req.UserID).Scan(&userCoins)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userCoins < price {
+		slog.DebugContext(ctx, "not enough coins", "coins", userCoins, "price", price)
+		return nil, repository.ErrInsufficientCoins
+	}
+
+	// Обновляем баланс
+	_, err = tx.Exec(ctx,
+		"UPDATE users SET coins = coins - $1 WHERE id = $2",
+		price, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if variantID != nil {
+		_, err = tx.Exec(ctx, "UPDATE merch_variants SET stock = stock - 1 WHERE id = $1", *variantID)
+	} else {
+		_, err = tx.Exec(ctx, "UPDATE merch_items SET stock = stock - 1 WHERE id = $1", merchID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Добавляем товар в инвентарь
+	_, err = tx.Exec(ctx,
+		`INSERT INTO user_inventory (user_id, merch_id, variant_id, quantity)
+         VALUES ($1, $2, $3, 1)
+         ON CONFLICT (user_id, merch_id, (COALESCE(variant_id, 0)))
+         DO UPDATE SET quantity = user_inventory.quantity + 1`,
+		ownerID, merchID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Записываем строку заказа для выдачи вместе с применённой скидкой
+	var couponCode string
+	if coupon != nil {
+		couponCode = coupon.Code
+	}
+	var orderID uint
+	err = tx.QueryRow(ctx,
+		`INSERT INTO order_lines (user_id, recipient_id, merch_id, variant_id, price, discount, promotion_id, coupon_code)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		req.UserID, recipientID, merchID, variantID, price, listPrice-price, promotionID, couponCode).Scan(&orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if coupon != nil {
+		_, err = tx.Exec(ctx, "UPDATE coupons SET used_count = used_count + 1 WHERE id = $1", coupon.ID)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(ctx,
+			"INSERT INTO coupon_redemptions (coupon_id, user_id, order_line_id) VALUES ($1, $2, $3)",
+			coupon.ID, req.UserID, orderID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.Purchase{
+		OrderID:     orderID,
+		UserID:      req.UserID,
+		RecipientID: recipientID,
+		Recipient:   recipientName(recipientID, req.Recipient),
+		Item:        req.Item,
+		MerchID:     merchID,
+		SKU:         req.SKU,
+		ListPrice:   listPrice,
+		Discount:    listPrice - price,
+		PromotionID: promotionID,
+		Coupon:      couponCode,
+		Price:       price,
+		Balance:     userCoins - price,
+	}, nil
+}
+
+// bestPromotion выбирает действующую акцию с наибольшей скидкой для товара или его категории
+func bestPromotion(ctx context.Context, tx pgx.Tx, merchID uint, category string, price int) (*uint, int, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, kind, value FROM promotions
+         WHERE (merch_id = $1 OR (category <> '' AND category = $2))
+           AND starts_at <= now() AND ends_at > now()`,
+		merchID, category)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var bestID *uint
+	var best int
+	for rows.Next() {
+		var id uint
+		var d model.Discount
+		if err := rows.Scan(&id, &d.Kind, &d.Value); err != nil {
+			return nil, 0, err
+		}
+		if amount := d.Amount(price); amount > best {
+			bestID, best = &id, amount
+		}
+	}
+	return bestID, best, rows.Err()
+}
+
+// lockCoupon блокирует купон до конца транзакции и проверяет, что его можно применить.
+// Срок сверяется с now() базы, как и у акций в bestPromotion, а не с часами приложения.
+func lockCoupon(ctx context.Context, tx pgx.Tx, code string, userID, merchID uint, category string) (*model.Coupon, error) {
+	var c model.Coupon
+	var now time.Time
+	err := tx.QueryRow(ctx,
+		`SELECT id, code, merch_id, category, kind, value, max_uses, used_count, starts_at, ends_at, now()
+         FROM coupons WHERE code = $1 FOR UPDATE`,
+		code).Scan(&c.ID, &c.Code, &c.MerchID, &c.Category, &c.Kind, &c.Value, &c.MaxUses, &c.UsedCount, &c.StartsAt, &c.EndsAt, &now)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrCouponInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !c.Active(now) {
+		return nil, repository.ErrCouponInvalid
+	}
+	if !c.AppliesTo(merchID, category) {
+		return nil, repository.ErrCouponNotApplies
+	}
+	if c.Exhausted() {
+		return nil, repository.ErrCouponUsed
+	}
+
+	var redeemed bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2)",
+		c.ID, userID).Scan(&redeemed)
+	if err != nil {
+		return nil, err
+	}
+	if redeemed {
+		return nil, repository.ErrCouponUsed
+	}
+	return &c, nil
+}
+
+func (r *repo) ListInventory(ctx context.Context, userID uint) ([]model.InventoryItem, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		`SELECT m.name, i.quantity, COALESCE(v.sku, ''), COALESCE(v.size, ''), COALESCE(v.color, '')
+         FROM user_inventory i
+         JOIN merch_items m ON m.id = i.merch_id
+         LEFT JOIN merch_variants v ON v.id = i.variant_id
+         WHERE i.user_id = $1 AND i.quantity > 0
+         ORDER BY m.name, v.sku`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.InventoryItem
+	for rows.Next() {
+		var item model.InventoryItem
+		if err := rows.Scan(&item.Type, &item.Quantity, &item.SKU, &item.Size, &item.Color); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func recipientName(recipientID *uint, name string) string {
+	if recipientID == nil {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,253 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+var (
+	promotionColumns = []string{"id", "kind", "value"}
+	couponColumns    = []string{"id", "code", "merch_id", "category", "kind", "value", "max_uses", "used_count", "starts_at", "ends_at", "now"}
+	testNow          = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// expectItem - товар cup (id 2) из категории accessories
+func expectItem(m pgxmock.PgxPoolIface, name string, hasVariants bool) {
+	m.ExpectQuery("SELECT id, category, EXISTS (.+) FROM merch_items m WHERE name = \\$1").WithArgs(name).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "category", "exists"}).AddRow(uint(2), "accessories", hasVariants))
+}
+
+func expectItemStock(m pgxmock.PgxPoolIface, price, stock int) {
+	m.ExpectQuery("SELECT price, stock FROM merch_items WHERE id = \\$1 FOR UPDATE").WithArgs(uint(2)).
+		WillReturnRows(pgxmock.NewRows([]string{"price", "stock"}).AddRow(price, stock))
+}
+
+func expectNoPromotions(m pgxmock.PgxPoolIface) {
+	m.ExpectQuery("FROM promotions").WithArgs(uint(2), "accessories").WillReturnRows(pgxmock.NewRows(promotionColumns))
+}
+
+func expectCoins(m pgxmock.PgxPoolIface, coins int) {
+	m.ExpectQuery("SELECT coins FROM users WHERE id = \\$1 FOR UPDATE").WithArgs(uint(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"coins"}).AddRow(coins))
+}
+
+func TestRepo_BuyMerch(t *testing.T) {
+	variantID := uint(11)
+	promotionID := uint(4)
+	bobID := uint(3)
+
+	tests := []struct {
+		name        string
+		req         model.PurchaseRequest
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expected    *model.Purchase
+		expectedErr error
+	}{
+		{
+			name: "Покупка товара без вариантов",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", false)
+				expectItemStock(m, 20, 100)
+				expectNoPromotions(m)
+				expectCoins(m, 1000)
+				m.ExpectExec("UPDATE users SET coins = coins - \\$1 WHERE id = \\$2").WithArgs(20, uint(1)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectExec("UPDATE merch_items SET stock = stock - 1").WithArgs(uint(2)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectExec("INSERT INTO user_inventory").WithArgs(uint(1), uint(2), (*uint)(nil)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				m.ExpectQuery("INSERT INTO order_lines").
+					WithArgs(uint(1), (*uint)(nil), uint(2), (*uint)(nil), 20, 0, (*uint)(nil), "").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(7)))
+				m.ExpectCommit()
+			},
+			expected: &model.Purchase{OrderID: 7, UserID: 1, Item: "cup", MerchID: 2, ListPrice: 20, Price: 20, Balance: 980},
+		},
+		{
+			// Акция и купон складываются: купон считается от цены после акции
+			name: "Подарок варианта по акции и купону",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup", SKU: "CUP-RED", Coupon: "CUP5", Recipient: "bob"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", true)
+				m.ExpectQuery("FROM merch_variants v JOIN merch_items m (.+) FOR UPDATE OF v").WithArgs(uint(2), "CUP-RED").
+					WillReturnRows(pgxmock.NewRows([]string{"id", "price", "stock"}).AddRow(variantID, 30, 4))
+				m.ExpectQuery("FROM promotions").WithArgs(uint(2), "accessories").
+					WillReturnRows(pgxmock.NewRows(promotionColumns).
+						AddRow(uint(3), model.DiscountFixed, 2).
+						AddRow(promotionID, model.DiscountPercent, 10))
+				m.ExpectQuery("FROM coupons WHERE code = \\$1 FOR UPDATE").WithArgs("CUP5").
+					WillReturnRows(pgxmock.NewRows(couponColumns).
+						AddRow(uint(9), "CUP5", (*uint)(nil), "accessories", model.DiscountFixed, 5, (*int)(nil), 0, (*time.Time)(nil), (*time.Time)(nil), testNow))
+				m.ExpectQuery("SELECT EXISTS (.+) FROM coupon_redemptions").WithArgs(uint(9), uint(1)).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectQuery("SELECT id FROM users WHERE username = \\$1").WithArgs("bob").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(bobID))
+				expectCoins(m, 1000)
+				m.ExpectExec("UPDATE users SET coins").WithArgs(22, uint(1)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectExec("UPDATE merch_variants SET stock = stock - 1").WithArgs(variantID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				// Товар попадает в инвентарь получателя
+				m.ExpectExec("INSERT INTO user_inventory").WithArgs(bobID, uint(2), &variantID).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				m.ExpectQuery("INSERT INTO order_lines").
+					WithArgs(uint(1), &bobID, uint(2), &variantID, 22, 8, &promotionID, "CUP5").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(7)))
+				m.ExpectExec("UPDATE coupons SET used_count = used_count \\+ 1").WithArgs(uint(9)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectExec("INSERT INTO coupon_redemptions").WithArgs(uint(9), uint(1), uint(7)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				m.ExpectCommit()
+			},
+			expected: &model.Purchase{
+				OrderID: 7, UserID: 1, RecipientID: &bobID, Recipient: "bob", Item: "cup", MerchID: 2, SKU: "CUP-RED",
+				ListPrice: 30, Discount: 8, PromotionID: &promotionID, Coupon: "CUP5", Price: 22, Balance: 978,
+			},
+		},
+		{
+			name: "Неизвестный товар",
+			req:  model.PurchaseRequest{UserID: 1, Item: "yacht"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("FROM merch_items m WHERE name = \\$1").WithArgs("yacht").WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+		{
+			name: "Неизвестный вариант",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup", SKU: "CUP-GOLD"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", true)
+				m.ExpectQuery("FROM merch_variants v").WithArgs(uint(2), "CUP-GOLD").WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrVariantNotFound,
+		},
+		{
+			name: "Товар с вариантами без варианта",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", true)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrVariantRequired,
+		},
+		{
+			name: "Товар закончился",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", false)
+				expectItemStock(m, 20, 0)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrOutOfStock,
+		},
+		{
+			name: "Купон просрочен",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup", Coupon: "OLD"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				endsAt := testNow.Add(-time.Minute)
+				m.ExpectBegin()
+				expectItem(m, "cup", false)
+				expectItemStock(m, 20, 100)
+				expectNoPromotions(m)
+				// Срок сверяется с now() базы, а не с часами приложения
+				m.ExpectQuery("FROM coupons WHERE code = \\$1 FOR UPDATE").WithArgs("OLD").
+					WillReturnRows(pgxmock.NewRows(couponColumns).
+						AddRow(uint(9), "OLD", (*uint)(nil), "", model.DiscountFixed, 5, (*int)(nil), 0, (*time.Time)(nil), &endsAt, testNow))
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrCouponInvalid,
+		},
+		{
+			name: "Подарок неизвестному получателю",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup", Recipient: "nobody"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", false)
+				expectItemStock(m, 20, 100)
+				expectNoPromotions(m)
+				m.ExpectQuery("SELECT id FROM users WHERE username = \\$1").WithArgs("nobody").WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrRecipientNotFound,
+		},
+		{
+			name: "Покупатель удалён",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", false)
+				expectItemStock(m, 20, 100)
+				expectNoPromotions(m)
+				m.ExpectQuery("SELECT coins FROM users WHERE id = \\$1 FOR UPDATE").WithArgs(uint(1)).WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrUserNotFound,
+		},
+		{
+			name: "Недостаточно монет",
+			req:  model.PurchaseRequest{UserID: 1, Item: "cup"},
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				expectItem(m, "cup", false)
+				expectItemStock(m, 20, 100)
+				expectNoPromotions(m)
+				expectCoins(m, 19)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrInsufficientCoins,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			purchase, err := NewRepository(mock).BuyMerch(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, purchase)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListInventory(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("FROM user_inventory i (.+) WHERE i.user_id = \\$1 AND i.quantity > 0").WithArgs(uint(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"name", "quantity", "sku", "size", "color"}).
+			AddRow("cup", 2, "", "", "").
+			AddRow("hoody", 1, "HOODY-GRY-XL", "XL", "grey"))
+
+	items, err := NewRepository(mock).ListInventory(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.InventoryItem{
+		{Type: "cup", Quantity: 2},
+		{Type: "hoody", Quantity: 1, SKU: "HOODY-GRY-XL", Size: "XL", Color: "grey"},
+	}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,70 @@
+package login
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"time"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.LoginAttemptRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) Get(ctx context.Context, username, ip string) (*model.LoginAttempts, error) {
+	attempts := model.LoginAttempts{Username: username, IP: ip}
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		"SELECT failures, last_failure_at, locked_until FROM login_attempts WHERE username = $1 AND ip = $2",
+		username, ip).Scan(&attempts.Failures, &attempts.LastFailureAt, &attempts.LockedUntil)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+func (r *repo) RecordFailure(ctx context.Context, username, ip string, now, since time.Time) (*model.LoginAttempts, error) {
+	attempts := model.LoginAttempts{Username: username, IP: ip}
+	// Счётчик меняется одним запросом, чтобы параллельные попытки не терялись
+	err := pg.Conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO login_attempts (username, ip, failures, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (username, ip) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < $4 THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures, last_failure_at, locked_until`,
+		username, ip, now, since).Scan(&attempts.Failures, &attempts.LastFailureAt, &attempts.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+func (r *repo) Lock(ctx context.Context, username, ip string, until time.Time) error {
+	_, err := pg.Conn(ctx, r.db).Exec(ctx,
+		"UPDATE login_attempts SET locked_until = $3 WHERE username = $1 AND ip = $2",
+		username, ip, until)
+	return err
+}
+
+func (r *repo) Reset(ctx context.Context, username, ip string) error {
+	_, err := pg.Conn(ctx, r.db).Exec(ctx, "DELETE FROM login_attempts WHERE username = $1 AND ip = $2", username, ip)
+	return err
+}
+
+func (r *repo) UnlockUser(ctx context.Context, username string) (int64, error) {
+	tag, err := pg.Conn(ctx, r.db).Exec(ctx,
+		"DELETE FROM login_attempts WHERE username = $1", username)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,130 @@
+package login
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+func TestRepo_Get(t *testing.T) {
+	lockedUntil := time.Date(2025, 3, 1, 12, 1, 0, 0, time.UTC)
+	lastFailure := lockedUntil.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expected    *model.LoginAttempts
+		expectedErr error
+	}{
+		{
+			name: "Пара заблокирована",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("SELECT failures, last_failure_at, locked_until FROM login_attempts").
+					WithArgs("alice", "10.0.0.1").
+					WillReturnRows(pgxmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}).
+						AddRow(3, lastFailure, &lockedUntil))
+			},
+			expected: &model.LoginAttempts{Username: "alice", IP: "10.0.0.1", Failures: 3, LastFailureAt: lastFailure, LockedUntil: &lockedUntil},
+		},
+		{
+			name: "Неудачных входов не было",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("SELECT failures, last_failure_at, locked_until FROM login_attempts").
+					WithArgs("alice", "10.0.0.1").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			attempts, err := NewRepository(mock).Get(context.Background(), "alice", "10.0.0.1")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, attempts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_RecordFailure(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-10 * time.Minute)
+	// Счётчик сбрасывается в том же запросе, если прошлая неудача старше since
+	mock.ExpectQuery(`INSERT INTO login_attempts (.+) ON CONFLICT \(username, ip\) DO UPDATE SET\s+failures = CASE WHEN login_attempts.last_failure_at < \$4 THEN 1`).
+		WithArgs("alice", "10.0.0.1", now, since).
+		WillReturnRows(pgxmock.NewRows([]string{"failures", "last_failure_at", "locked_until"}).
+			AddRow(2, now, (*time.Time)(nil)))
+
+	attempts, err := NewRepository(mock).RecordFailure(context.Background(), "alice", "10.0.0.1", now, since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LoginAttempts{Username: "alice", IP: "10.0.0.1", Failures: 2, LastFailureAt: now}, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_Lock(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	until := time.Date(2025, 3, 1, 12, 1, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE login_attempts SET locked_until = \\$3").
+		WithArgs("alice", "10.0.0.1", until).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = NewRepository(mock).Lock(context.Background(), "alice", "10.0.0.1", until)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_Reset(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectExec("DELETE FROM login_attempts WHERE username = \\$1 AND ip = \\$2").
+		WithArgs("alice", "10.0.0.1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	err = NewRepository(mock).Reset(context.Background(), "alice", "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_UnlockUser(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	// Разблокировка снимает записи со всех IP
+	mock.ExpectExec("DELETE FROM login_attempts WHERE username = \\$1$").
+		WithArgs("alice").
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+	cleared, err := NewRepository(mock).UnlockUser(context.Background(), "alice")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cleared)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
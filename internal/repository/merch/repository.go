@@ -0,0 +1,144 @@
+package merch
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.MerchRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) GetItemByName(ctx context.Context, name string) (*model.MerchItem, error) {
+	var item model.MerchItem
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		"SELECT id, name, price, stock, category FROM merch_items WHERE name = $1",
+		name).Scan(&item.ID, &item.Name, &item.Price, &item.Stock, &item.Category)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *repo) ListItems(ctx context.Context) ([]model.MerchItem, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx, "SELECT id, name, price, stock, category FROM merch_items ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.MerchItem
+	for rows.Next() {
+		var item model.MerchItem
+		if err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.Stock, &item.Category); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *repo) UpdateItem(ctx context.Context, name string, price, stock int) (*model.MerchItem, *model.MerchItem, error) {
+	var before, after model.MerchItem
+	// Старые значения берём из той же строки под блокировкой, чтобы не потерять переход цены/остатка
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		`UPDATE merch_items m SET price = $2, stock = $3
+         FROM (SELECT id, price, stock FROM merch_items WHERE name = $1 FOR UPDATE) old
+         WHERE m.id = old.id
+         RETURNING m.id, m.name, m.category, old.price, old.stock, m.price, m.stock,
+                   EXISTS (SELECT 1 FROM merch_variants v WHERE v.merch_id = m.id)`,
+		name, price, stock).Scan(&after.ID, &after.Name, &after.Category, &before.Price, &before.Stock,
+		&after.Price, &after.Stock, &after.HasVariants)
+	if err == pgx.ErrNoRows {
+		return nil, nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	before.ID, before.Name, before.Category, before.HasVariants = after.ID, after.Name, after.Category, after.HasVariants
+	return &before, &after, nil
+}
+
+func (r *repo) ListVariants(ctx context.Context) ([]model.MerchVariant, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		"SELECT id, merch_id, sku, size, color, price_override, stock FROM merch_variants ORDER BY merch_id, sku")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []model.MerchVariant
+	for rows.Next() {
+		var v model.MerchVariant
+		if err := rows.Scan(&v.ID, &v.MerchID, &v.SKU, &v.Size, &v.Color, &v.PriceOverride, &v.Stock); err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, rows.Err()
+}
+
+func (r *repo) UpsertVariant(ctx context.Context, itemName string, variant model.MerchVariant) (*model.MerchVariant, *model.MerchVariant, error) {
+	var before, after *model.MerchVariant
+	err := pg.WithTx(ctx, r.db, func(tx pgx.Tx) (err error) {
+		before, after, err = upsertVariant(ctx, tx, itemName, variant)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// upsertVariant возвращает вариант до и после изменения, before == nil для нового варианта
+func upsertVariant(ctx context.Context, tx pgx.Tx, itemName string, variant model.MerchVariant) (_ *model.MerchVariant, _ *model.MerchVariant, err error) {
+	var merchID uint
+	err = tx.QueryRow(ctx, "SELECT id FROM merch_items WHERE name = $1", itemName).Scan(&merchID)
+	if err == pgx.ErrNoRows {
+		return nil, nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var before *model.MerchVariant
+	var old model.MerchVariant
+	err = tx.QueryRow(ctx,
+		"SELECT id, merch_id, sku, size, color, price_override, stock FROM merch_variants WHERE sku = $1 FOR UPDATE",
+		variant.SKU).Scan(&old.ID, &old.MerchID, &old.SKU, &old.Size, &old.Color, &old.PriceOverride, &old.Stock)
+	switch {
+	case err == pgx.ErrNoRows:
+	case err != nil:
+		return nil, nil, err
+	case old.MerchID != merchID:
+		return nil, nil, repository.ErrSKUTaken
+	default:
+		before = &old
+	}
+
+	after := variant
+	after.MerchID = merchID
+	err = tx.QueryRow(ctx,
+		`INSERT INTO merch_variants (merch_id, sku, size, color, price_override, stock)
+         VALUES ($1, $2, $3, $4, $5, $6)
+         ON CONFLICT (sku) DO UPDATE
+         SET size = EXCLUDED.size, color = EXCLUDED.color,
+             price_override = EXCLUDED.price_override, stock = EXCLUDED.stock
+         RETURNING id`,
+		merchID, variant.SKU, variant.Size, variant.Color, variant.PriceOverride, variant.Stock).Scan(&after.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return before, &after, nil
+}
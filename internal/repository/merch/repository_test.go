@@ -0,0 +1,231 @@
+package merch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+var variantColumns = []string{"id", "merch_id", "sku", "size", "color", "price_override", "stock"}
+
+func TestRepo_GetItemByName(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expected    *model.MerchItem
+		expectedErr error
+	}{
+		{
+			name: "Товар найден",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("SELECT id, name, price, stock, category FROM merch_items WHERE name = \\$1").
+					WithArgs("cup").
+					WillReturnRows(pgxmock.NewRows([]string{"id", "name", "price", "stock", "category"}).
+						AddRow(uint(2), "cup", 20, 100, "accessories"))
+			},
+			expected: &model.MerchItem{ID: 2, Name: "cup", Price: 20, Stock: 100, Category: "accessories"},
+		},
+		{
+			name: "Товар не найден",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("SELECT id, name, price, stock, category FROM merch_items").
+					WithArgs("cup").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			item, err := NewRepository(mock).GetItemByName(context.Background(), "cup")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, item)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListItems(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT id, name, price, stock, category FROM merch_items ORDER BY name").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "price", "stock", "category"}).
+			AddRow(uint(2), "cup", 20, 100, "accessories").
+			AddRow(uint(7), "hoody", 300, 0, "clothes"))
+
+	items, err := NewRepository(mock).ListItems(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MerchItem{
+		{ID: 2, Name: "cup", Price: 20, Stock: 100, Category: "accessories"},
+		{ID: 7, Name: "hoody", Price: 300, Stock: 0, Category: "clothes"},
+	}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_UpdateItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(m pgxmock.PgxPoolIface)
+		expectedBefore *model.MerchItem
+		expectedAfter  *model.MerchItem
+		expectedErr    error
+	}{
+		{
+			name: "Цена и остаток изменены",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery(`UPDATE merch_items m SET price = \$2, stock = \$3\s+FROM \(SELECT id, price, stock FROM merch_items WHERE name = \$1 FOR UPDATE\) old`).
+					WithArgs("cup", 15, 50).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "name", "category", "old_price", "old_stock", "price", "stock", "has_variants"}).
+						AddRow(uint(2), "cup", "accessories", 20, 0, 15, 50, false))
+			},
+			// Старые значения берутся из той же строки, остальные поля совпадают
+			expectedBefore: &model.MerchItem{ID: 2, Name: "cup", Category: "accessories", Price: 20, Stock: 0},
+			expectedAfter:  &model.MerchItem{ID: 2, Name: "cup", Category: "accessories", Price: 15, Stock: 50},
+		},
+		{
+			name: "Товар не найден",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("UPDATE merch_items").
+					WithArgs("cup", 15, 50).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			before, after, err := NewRepository(mock).UpdateItem(context.Background(), "cup", 15, 50)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedBefore, before)
+			assert.Equal(t, tt.expectedAfter, after)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListVariants(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	override := 350
+	mock.ExpectQuery("SELECT id, merch_id, sku, size, color, price_override, stock FROM merch_variants ORDER BY merch_id, sku").
+		WillReturnRows(pgxmock.NewRows(variantColumns).
+			AddRow(uint(1), uint(7), "HOODY-GRY-M", "M", "grey", (*int)(nil), 5).
+			AddRow(uint(2), uint(7), "HOODY-GRY-XL", "XL", "grey", &override, 3))
+
+	variants, err := NewRepository(mock).ListVariants(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MerchVariant{
+		{ID: 1, MerchID: 7, SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 5},
+		{ID: 2, MerchID: 7, SKU: "HOODY-GRY-XL", Size: "XL", Color: "grey", PriceOverride: &override, Stock: 3},
+	}, variants)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_UpsertVariant(t *testing.T) {
+	variant := model.MerchVariant{SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 8}
+
+	tests := []struct {
+		name           string
+		mockSetup      func(m pgxmock.PgxPoolIface)
+		expectedBefore *model.MerchVariant
+		expectedAfter  *model.MerchVariant
+		expectedErr    error
+	}{
+		{
+			name: "Новый вариант",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM merch_items WHERE name = \\$1").WithArgs("hoody").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(7)))
+				m.ExpectQuery("FROM merch_variants WHERE sku = \\$1 FOR UPDATE").WithArgs("HOODY-GRY-M").
+					WillReturnError(pgx.ErrNoRows)
+				m.ExpectQuery("INSERT INTO merch_variants (.+) ON CONFLICT \\(sku\\) DO UPDATE").
+					WithArgs(uint(7), "HOODY-GRY-M", "M", "grey", (*int)(nil), 8).
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(3)))
+				m.ExpectCommit()
+			},
+			expectedAfter: &model.MerchVariant{ID: 3, MerchID: 7, SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 8},
+		},
+		{
+			name: "Изменение существующего варианта",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM merch_items WHERE name = \\$1").WithArgs("hoody").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(7)))
+				m.ExpectQuery("FROM merch_variants WHERE sku = \\$1 FOR UPDATE").WithArgs("HOODY-GRY-M").
+					WillReturnRows(pgxmock.NewRows(variantColumns).AddRow(uint(1), uint(7), "HOODY-GRY-M", "M", "grey", (*int)(nil), 0))
+				m.ExpectQuery("INSERT INTO merch_variants").
+					WithArgs(uint(7), "HOODY-GRY-M", "M", "grey", (*int)(nil), 8).
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(1)))
+				m.ExpectCommit()
+			},
+			expectedBefore: &model.MerchVariant{ID: 1, MerchID: 7, SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 0},
+			expectedAfter:  &model.MerchVariant{ID: 1, MerchID: 7, SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 8},
+		},
+		{
+			name: "SKU занят другим товаром",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM merch_items WHERE name = \\$1").WithArgs("hoody").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(7)))
+				m.ExpectQuery("FROM merch_variants WHERE sku = \\$1 FOR UPDATE").WithArgs("HOODY-GRY-M").
+					WillReturnRows(pgxmock.NewRows(variantColumns).AddRow(uint(1), uint(8), "HOODY-GRY-M", "M", "grey", (*int)(nil), 0))
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrSKUTaken,
+		},
+		{
+			name: "Товар не найден",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM merch_items WHERE name = \\$1").WithArgs("hoody").
+					WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			before, after, err := NewRepository(mock).UpsertVariant(context.Background(), "hoody", variant)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedBefore, before)
+			assert.Equal(t, tt.expectedAfter, after)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
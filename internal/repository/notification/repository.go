@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"context"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.NotificationRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) CreateNotifications(ctx context.Context, notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint, len(notifications))
+	kinds := make([]string, len(notifications))
+	merchIDs := make([]uint, len(notifications))
+	messages := make([]string, len(notifications))
+	for i, n := range notifications {
+		userIDs[i], kinds[i], merchIDs[i], messages[i] = n.UserID, n.Kind, n.MerchID, n.Message
+	}
+
+	// MerchID == 0 - уведомление не о товаре, пишем NULL, иначе не пройдёт внешний ключ на merch_items
+	_, err := pg.Conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO notifications (user_id, kind, merch_id, message)
+         SELECT user_id, kind, NULLIF(merch_id, 0), message
+         FROM unnest($1::int[], $2::text[], $3::int[], $4::text[]) AS n (user_id, kind, merch_id, message)`,
+		userIDs, kinds, merchIDs, messages)
+	return err
+}
+
+func (r *repo) ListByUser(ctx context.Context, userID uint, unreadOnly bool) ([]model.Notification, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		`SELECT id, user_id, kind, COALESCE(merch_id, 0), message, read_at, created_at
+         FROM notifications
+         WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+         ORDER BY created_at DESC, id DESC`,
+		userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var n model.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.MerchID, &n.Message, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *repo) MarkRead(ctx context.Context, userID, id uint) (bool, error) {
+	tag, err := pg.Conn(ctx, r.db).Exec(ctx,
+		"UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP) WHERE id = $1 AND user_id = $2",
+		id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+
+	"merchio/internal/model"
+)
+
+func TestRepo_CreateNotifications(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+	repo := &repo{db: mock}
+
+	// Нулевой MerchID уходит в NULLIF и сохраняется как NULL
+	mock.ExpectExec(`INSERT INTO notifications (.+) SELECT user_id, kind, NULLIF\(merch_id, 0\), message FROM unnest`).
+		WithArgs([]uint{2, 3}, []string{model.NotificationGift, model.NotificationPriceDrop}, []uint{0, 6},
+			[]string{"alice sent you a gift", "hoody is now 250 coins instead of 300"}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.CreateNotifications(context.Background(), []model.Notification{
+		{UserID: 2, Kind: model.NotificationGift, Message: "alice sent you a gift"},
+		{UserID: 3, Kind: model.NotificationPriceDrop, MerchID: 6, Message: "hoody is now 250 coins instead of 300"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_CreateNotifications_Empty(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+	repo := &repo{db: mock}
+
+	assert.NoError(t, repo.CreateNotifications(context.Background(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_CreateNotifications_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+	repo := &repo{db: mock}
+
+	dbErr := errors.New("ошибка БД")
+	mock.ExpectExec("INSERT INTO notifications").WillReturnError(dbErr)
+
+	err = repo.CreateNotifications(context.Background(), []model.Notification{{UserID: 2, Kind: model.NotificationGift}})
+
+	assert.ErrorIs(t, err, dbErr)
+}
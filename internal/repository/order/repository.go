@@ -0,0 +1,92 @@
+package order
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+const selectOrderLines = `SELECT o.id, o.user_id, b.username, o.recipient_id, COALESCE(rc.username, ''),
+       o.merch_id, m.name, COALESCE(v.sku, ''), o.price,
+       o.discount, o.promotion_id, o.coupon_code, o.status, o.pickup_location, o.created_at, o.updated_at
+FROM order_lines o
+JOIN users b ON b.id = o.user_id
+LEFT JOIN users rc ON rc.id = o.recipient_id
+JOIN merch_items m ON m.id = o.merch_id
+LEFT JOIN merch_variants v ON v.id = o.variant_id`
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.OrderRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) GetOrderLine(ctx context.Context, id uint) (*model.OrderLine, error) {
+	line, err := scanOrderLine(pg.Conn(ctx, r.db).QueryRow(ctx, selectOrderLines+" WHERE o.id = $1", id))
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+func (r *repo) ListByUser(ctx context.Context, userID uint) ([]model.OrderLine, error) {
+	// В историю попадают и свои покупки, и полученные подарки
+	return r.list(ctx, selectOrderLines+" WHERE o.user_id = $1 OR o.recipient_id = $1 ORDER BY o.created_at DESC, o.id DESC", userID)
+}
+
+func (r *repo) ListByStatus(ctx context.Context, status string) ([]model.OrderLine, error) {
+	return r.list(ctx, selectOrderLines+" WHERE $1 = '' OR o.status = $1 ORDER BY o.created_at, o.id", status)
+}
+
+func (r *repo) UpdateStatus(ctx context.Context, id uint, from, to, pickupLocation string) (*model.OrderLine, error) {
+	tag, err := pg.Conn(ctx, r.db).Exec(ctx,
+		`UPDATE order_lines
+         SET status = $3,
+             pickup_location = COALESCE(NULLIF($4, ''), pickup_location),
+             updated_at = CURRENT_TIMESTAMP
+         WHERE id = $1 AND status = $2`,
+		id, from, to, pickupLocation)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, repository.ErrStatusChanged
+	}
+	return r.GetOrderLine(ctx, id)
+}
+
+func (r *repo) list(ctx context.Context, query string, args ...interface{}) ([]model.OrderLine, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []model.OrderLine
+	for rows.Next() {
+		line, err := scanOrderLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, *line)
+	}
+	return lines, rows.Err()
+}
+
+func scanOrderLine(row pgx.Row) (*model.OrderLine, error) {
+	var line model.OrderLine
+	err := row.Scan(&line.ID, &line.UserID, &line.Buyer, &line.RecipientID, &line.Recipient,
+		&line.MerchID, &line.Item, &line.SKU, &line.Price,
+		&line.Discount, &line.PromotionID, &line.CouponCode, &line.Status, &line.PickupLocation, &line.CreatedAt, &line.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
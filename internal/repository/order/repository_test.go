@@ -0,0 +1,152 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+var (
+	orderColumns = []string{"id", "user_id", "buyer", "recipient_id", "recipient", "merch_id", "item", "sku", "price",
+		"discount", "promotion_id", "coupon_code", "status", "pickup_location", "created_at", "updated_at"}
+	testCreatedAt = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func orderRow(rows *pgxmock.Rows, id uint, status, pickupLocation string) *pgxmock.Rows {
+	return rows.AddRow(id, uint(1), "alice", (*uint)(nil), "", uint(2), "cup", "", 20,
+		0, (*uint)(nil), "", status, pickupLocation, testCreatedAt, testCreatedAt)
+}
+
+func orderLine(id uint, status, pickupLocation string) *model.OrderLine {
+	return &model.OrderLine{ID: id, UserID: 1, Buyer: "alice", MerchID: 2, Item: "cup", Price: 20,
+		Status: status, PickupLocation: pickupLocation, CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt}
+}
+
+func TestRepo_GetOrderLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expected    *model.OrderLine
+		expectedErr error
+	}{
+		{
+			name: "Заказ найден",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("FROM order_lines o (.+) WHERE o.id = \\$1").WithArgs(uint(5)).
+					WillReturnRows(orderRow(pgxmock.NewRows(orderColumns), 5, model.FulfilmentPlaced, ""))
+			},
+			expected: orderLine(5, model.FulfilmentPlaced, ""),
+		},
+		{
+			name: "Заказ не найден",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectQuery("FROM order_lines o").WithArgs(uint(5)).WillReturnError(pgx.ErrNoRows)
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			line, err := NewRepository(mock).GetOrderLine(context.Background(), 5)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, line)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	// В историю попадают и полученные подарки
+	mock.ExpectQuery("WHERE o.user_id = \\$1 OR o.recipient_id = \\$1 ORDER BY o.created_at DESC").WithArgs(uint(1)).
+		WillReturnRows(orderRow(orderRow(pgxmock.NewRows(orderColumns), 6, model.FulfilmentPlaced, ""), 5, model.FulfilmentDelivered, "desk 3"))
+
+	lines, err := NewRepository(mock).ListByUser(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.OrderLine{
+		*orderLine(6, model.FulfilmentPlaced, ""),
+		*orderLine(5, model.FulfilmentDelivered, "desk 3"),
+	}, lines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_ListByStatus(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("WHERE \\$1 = '' OR o.status = \\$1").WithArgs(model.FulfilmentPlaced).
+		WillReturnRows(pgxmock.NewRows(orderColumns))
+
+	lines, err := NewRepository(mock).ListByStatus(context.Background(), model.FulfilmentPlaced)
+
+	assert.NoError(t, err)
+	assert.Empty(t, lines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expected    *model.OrderLine
+		expectedErr error
+	}{
+		{
+			name: "Статус сменился",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectExec("UPDATE order_lines (.+) WHERE id = \\$1 AND status = \\$2").
+					WithArgs(uint(5), model.FulfilmentPacked, model.FulfilmentReadyForPickup, "desk 3").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectQuery("FROM order_lines o (.+) WHERE o.id = \\$1").WithArgs(uint(5)).
+					WillReturnRows(orderRow(pgxmock.NewRows(orderColumns), 5, model.FulfilmentReadyForPickup, "desk 3"))
+			},
+			expected: orderLine(5, model.FulfilmentReadyForPickup, "desk 3"),
+		},
+		{
+			// Другой сотрудник уже продвинул заказ
+			name: "Статус изменён параллельно",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectExec("UPDATE order_lines").
+					WithArgs(uint(5), model.FulfilmentPacked, model.FulfilmentReadyForPickup, "desk 3").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			expectedErr: repository.ErrStatusChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			line, err := NewRepository(mock).UpdateStatus(context.Background(), 5,
+				model.FulfilmentPacked, model.FulfilmentReadyForPickup, "desk 3")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, line)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
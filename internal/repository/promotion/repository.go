@@ -0,0 +1,93 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgconn"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+const uniqueViolation = "23505"
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.PromotionRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) CreatePromotion(ctx context.Context, promotion model.Promotion) (*model.Promotion, error) {
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO promotions (name, merch_id, category, kind, value, starts_at, ends_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)
+         RETURNING id, created_at`,
+		promotion.Name, promotion.MerchID, promotion.Category, promotion.Kind, promotion.Value,
+		promotion.StartsAt, promotion.EndsAt).Scan(&promotion.ID, &promotion.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *repo) ListPromotions(ctx context.Context) ([]model.Promotion, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		`SELECT id, name, merch_id, category, kind, value, starts_at, ends_at, created_at
+         FROM promotions ORDER BY starts_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []model.Promotion
+	for rows.Next() {
+		var p model.Promotion
+		err := rows.Scan(&p.ID, &p.Name, &p.MerchID, &p.Category, &p.Kind, &p.Value, &p.StartsAt, &p.EndsAt, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+func (r *repo) CreateCoupon(ctx context.Context, coupon model.Coupon) (*model.Coupon, error) {
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO coupons (code, merch_id, category, kind, value, max_uses, starts_at, ends_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+         RETURNING id, created_at`,
+		coupon.Code, coupon.MerchID, coupon.Category, coupon.Kind, coupon.Value,
+		coupon.MaxUses, coupon.StartsAt, coupon.EndsAt).Scan(&coupon.ID, &coupon.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return nil, repository.ErrAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *repo) ListCoupons(ctx context.Context) ([]model.Coupon, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		`SELECT id, code, merch_id, category, kind, value, max_uses, used_count, starts_at, ends_at, created_at
+         FROM coupons ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coupons []model.Coupon
+	for rows.Next() {
+		var c model.Coupon
+		err := rows.Scan(&c.ID, &c.Code, &c.MerchID, &c.Category, &c.Kind, &c.Value,
+			&c.MaxUses, &c.UsedCount, &c.StartsAt, &c.EndsAt, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, c)
+	}
+	return coupons, rows.Err()
+}
@@ -0,0 +1,136 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRepo_CreatePromotion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	merchID := uint(2)
+	promotion := model.Promotion{
+		Name: "cup week", MerchID: &merchID, Discount: model.Discount{Kind: model.DiscountPercent, Value: 10},
+		StartsAt: testNow, EndsAt: testNow.Add(7 * 24 * time.Hour),
+	}
+	mock.ExpectQuery("INSERT INTO promotions").
+		WithArgs("cup week", &merchID, "", model.DiscountPercent, 10, promotion.StartsAt, promotion.EndsAt).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(uint(4), testNow))
+
+	created, err := NewRepository(mock).CreatePromotion(context.Background(), promotion)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), created.ID)
+	assert.Equal(t, testNow, created.CreatedAt)
+	assert.Equal(t, "cup week", created.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_ListPromotions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("FROM promotions ORDER BY starts_at DESC, id DESC").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "merch_id", "category", "kind", "value", "starts_at", "ends_at", "created_at"}).
+			AddRow(uint(4), "clothes sale", (*uint)(nil), "clothes", model.DiscountFixed, 50, testNow, testNow.Add(time.Hour), testNow))
+
+	promotions, err := NewRepository(mock).ListPromotions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Promotion{{
+		ID: 4, Name: "clothes sale", Category: "clothes", Discount: model.Discount{Kind: model.DiscountFixed, Value: 50},
+		StartsAt: testNow, EndsAt: testNow.Add(time.Hour), CreatedAt: testNow,
+	}}, promotions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_CreateCoupon(t *testing.T) {
+	maxUses := 100
+	coupon := model.Coupon{
+		Code: "WELCOME", Category: "accessories", Discount: model.Discount{Kind: model.DiscountFixed, Value: 5}, MaxUses: &maxUses,
+	}
+
+	tests := []struct {
+		name        string
+		dbErr       error
+		expectedErr error
+	}{
+		{
+			name: "Купон создан",
+		},
+		{
+			name:        "Код уже занят",
+			dbErr:       &pgconn.PgError{Code: uniqueViolation},
+			expectedErr: repository.ErrAlreadyExists,
+		},
+		{
+			name:        "Ошибка БД",
+			dbErr:       errors.New("ошибка БД"),
+			expectedErr: errors.New("ошибка БД"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			query := mock.ExpectQuery("INSERT INTO coupons").
+				WithArgs("WELCOME", (*uint)(nil), "accessories", model.DiscountFixed, 5, &maxUses, (*time.Time)(nil), (*time.Time)(nil))
+			if tt.dbErr != nil {
+				query.WillReturnError(tt.dbErr)
+			} else {
+				query.WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(uint(9), testNow))
+			}
+
+			created, err := NewRepository(mock).CreateCoupon(context.Background(), coupon)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Nil(t, created)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, uint(9), created.ID)
+				assert.Equal(t, "WELCOME", created.Code)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListCoupons(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	endsAt := testNow.Add(time.Hour)
+	merchID := uint(2)
+	mock.ExpectQuery("FROM coupons ORDER BY created_at DESC, id DESC").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "code", "merch_id", "category", "kind", "value", "max_uses", "used_count", "starts_at", "ends_at", "created_at"}).
+			AddRow(uint(9), "CUP5", &merchID, "", model.DiscountFixed, 5, (*int)(nil), 3, (*time.Time)(nil), &endsAt, testNow))
+
+	coupons, err := NewRepository(mock).ListCoupons(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Coupon{{
+		ID: 9, Code: "CUP5", MerchID: &merchID, Discount: model.Discount{Kind: model.DiscountFixed, Value: 5},
+		UsedCount: 3, EndsAt: &endsAt, CreatedAt: testNow,
+	}}, coupons)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,11 +2,113 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"merchio/internal/model"
+	"time"
 )
 
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrVariantNotFound   = errors.New("variant not found")
+	ErrVariantRequired   = errors.New("variant required")
+	ErrOutOfStock        = errors.New("out of stock")
+	ErrInsufficientCoins = errors.New("insufficient coins")
+	ErrStatusChanged     = errors.New("status changed concurrently")
+	ErrAlreadyExists     = errors.New("already exists")
+	ErrCouponInvalid     = errors.New("coupon is unknown or not active")
+	ErrCouponNotApplies  = errors.New("coupon does not apply to the item")
+	ErrCouponUsed        = errors.New("coupon is used up")
+	ErrSelfTransfer      = errors.New("sender and receiver are the same user")
+	ErrSKUTaken          = errors.New("sku belongs to another item")
+)
+
+// TxManager выполняет fn в одной транзакции: репозитории, получившие ctx из fn, работают в ней.
+// Вложенный Do становится точкой сохранения, конфликт сериализации повторяет транзакцию целиком.
+type TxManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, username, password string) (int64, error)
 	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
+	GetUserByID(ctx context.Context, id int) (*model.User, error)
 	IsUserPresent(ctx context.Context, username string) (bool, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+}
+
+type PasswordResetRepository interface {
+	// CreateToken сохраняет токен, прежние неиспользованные токены пользователя перестают действовать
+	CreateToken(ctx context.Context, token model.PasswordResetToken) error
+	// ResetPassword гасит действующий токен и меняет пароль одной транзакцией.
+	// Для неизвестного, использованного или просроченного токена возвращает ErrNotFound.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) (userID int, err error)
+}
+
+type LoginAttemptRepository interface {
+	// Get возвращает ErrNotFound, если неудачных входов не было
+	Get(ctx context.Context, username, ip string) (*model.LoginAttempts, error)
+	// RecordFailure увеличивает счётчик, если прошлая неудача была раньше since, счёт начинается заново
+	RecordFailure(ctx context.Context, username, ip string, now, since time.Time) (*model.LoginAttempts, error)
+	Lock(ctx context.Context, username, ip string, until time.Time) error
+	Reset(ctx context.Context, username, ip string) error
+	// UnlockUser сбрасывает счётчики и блокировки пользователя со всех IP, возвращает число удалённых записей
+	UnlockUser(ctx context.Context, username string) (int64, error)
+}
+
+type AuditRepository interface {
+	Record(ctx context.Context, entry model.AuditEntry) error
+}
+
+type MerchRepository interface {
+	GetItemByName(ctx context.Context, name string) (*model.MerchItem, error)
+	ListItems(ctx context.Context) ([]model.MerchItem, error)
+	// UpdateItem возвращает состояние товара до и после изменения
+	UpdateItem(ctx context.Context, name string, price, stock int) (before, after *model.MerchItem, err error)
+	ListVariants(ctx context.Context) ([]model.MerchVariant, error)
+	// UpsertVariant создаёт или обновляет вариант по SKU, before == nil для нового варианта
+	UpsertVariant(ctx context.Context, itemName string, variant model.MerchVariant) (before, after *model.MerchVariant, err error)
+}
+
+type InventoryRepository interface {
+	// BuyMerch списывает монеты и остаток и добавляет товар в инвентарь одной транзакцией
+	// Действующая акция и купон применяются в той же транзакции, скидка записывается в строку заказа
+	BuyMerch(ctx context.Context, req model.PurchaseRequest) (*model.Purchase, error)
+	ListInventory(ctx context.Context, userID uint) ([]model.InventoryItem, error)
+}
+
+type TransferRepository interface {
+	// TransferCoins переводит монеты пользователю toUsername одной транзакцией
+	TransferCoins(ctx context.Context, fromID uint, toUsername string, amount int) (*model.CoinTransaction, error)
+	ListHistory(ctx context.Context, userID uint) (*model.CoinHistory, error)
+}
+
+type WishlistRepository interface {
+	AddItem(ctx context.Context, userID, merchID uint) error
+	RemoveItem(ctx context.Context, userID, merchID uint) (bool, error)
+	ListItems(ctx context.Context, userID uint) ([]model.WishlistEntry, error)
+	ListUserIDsByMerch(ctx context.Context, merchID uint) ([]uint, error)
+}
+
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, notifications []model.Notification) error
+	ListByUser(ctx context.Context, userID uint, unreadOnly bool) ([]model.Notification, error)
+	MarkRead(ctx context.Context, userID, id uint) (bool, error)
+}
+
+type OrderRepository interface {
+	GetOrderLine(ctx context.Context, id uint) (*model.OrderLine, error)
+	ListByUser(ctx context.Context, userID uint) ([]model.OrderLine, error)
+	// ListByStatus возвращает все строки заказов, пустой статус - без фильтра
+	ListByStatus(ctx context.Context, status string) ([]model.OrderLine, error)
+	// UpdateStatus меняет статус, только если текущий статус равен from
+	UpdateStatus(ctx context.Context, id uint, from, to, pickupLocation string) (*model.OrderLine, error)
+}
+
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, promotion model.Promotion) (*model.Promotion, error)
+	ListPromotions(ctx context.Context) ([]model.Promotion, error)
+	CreateCoupon(ctx context.Context, coupon model.Coupon) (*model.Coupon, error)
+	ListCoupons(ctx context.Context) ([]model.Coupon, error)
 }
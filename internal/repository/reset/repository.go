@@ -0,0 +1,56 @@
+package reset
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"time"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.PasswordResetRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) CreateToken(ctx context.Context, token model.PasswordResetToken) error {
+	return pg.WithTx(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"DELETE FROM password_reset_tokens WHERE user_id = $1 AND used_at IS NULL", token.UserID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx,
+			"INSERT INTO password_reset_tokens (token_hash, user_id, created_by, expires_at) VALUES ($1, $2, $3, $4)",
+			token.TokenHash, token.UserID, token.CreatedBy, token.ExpiresAt)
+		return err
+	})
+}
+
+func (r *repo) ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) (int, error) {
+	var userID int
+	err := pg.WithTx(ctx, r.db, func(tx pgx.Tx) error {
+		// Токен гасится условным UPDATE, поэтому два параллельных сброса одним токеном не пройдут
+		err := tx.QueryRow(ctx, `
+			UPDATE password_reset_tokens SET used_at = $2
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+			RETURNING user_id`,
+			tokenHash, now).Scan(&userID)
+		if err == pgx.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, "UPDATE users SET password = $2 WHERE id = $1", userID, passwordHash)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,84 @@
+package reset
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_CreateToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM password_reset_tokens").WithArgs(5).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("INSERT INTO password_reset_tokens").WithArgs("tokenhash", 5, 1, expiresAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	err = NewRepository(mock).CreateToken(context.Background(), model.PasswordResetToken{
+		TokenHash: "tokenhash", UserID: 5, CreatedBy: 1, ExpiresAt: expiresAt,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_ResetPassword(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expectedID  int
+		expectedErr error
+	}{
+		{
+			name: "Токен действует",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("UPDATE password_reset_tokens").WithArgs("tokenhash", now).
+					WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(5))
+				m.ExpectExec("UPDATE users SET password").WithArgs(5, "newhash").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectCommit()
+			},
+			expectedID: 5,
+		},
+		{
+			name: "Токен использован или просрочен",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("UPDATE password_reset_tokens").WithArgs("tokenhash", now).
+					WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			userID, err := NewRepository(mock).ResetPassword(context.Background(), "tokenhash", "newhash", now)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedID, userID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
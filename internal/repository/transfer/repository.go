@@ -0,0 +1,131 @@
+package transfer
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.TransferRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) TransferCoins(ctx context.Context, fromID uint, toUsername string, amount int) (*model.CoinTransaction, error) {
+	var transaction *model.CoinTransaction
+	err := pg.WithTx(ctx, r.db, func(tx pgx.Tx) (err error) {
+		transaction, err = transferCoins(ctx, tx, fromID, toUsername, amount)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "transfer committed", "transaction_id", transaction.ID, "to_id", transaction.ToID)
+	return transaction, nil
+}
+
+// transferCoins - перевод внутри транзакции, строки отправителя и получателя блокируются
+func transferCoins(ctx context.Context, tx pgx.Tx, fromID uint, toUsername string, amount int) (_ *model.CoinTransaction, err error) {
+	var toID uint
+	err = tx.QueryRow(ctx, "SELECT id FROM users WHERE username = $1", toUsername).Scan(&toID)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrRecipientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if toID == fromID {
+		return nil, repository.ErrSelfTransfer
+	}
+
+	// Блокируем обе строки в порядке id, чтобы встречные переводы не взаимоблокировались
+	rows, err := tx.Query(ctx,
+		"SELECT id, coins FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE",
+		fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	var senderCoins int
+	var senderFound bool
+	for rows.Next() {
+		var id uint
+		var coins int
+		if err := rows.Scan(&id, &coins); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if id == fromID {
+			senderCoins, senderFound = coins, true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !senderFound {
+		return nil, repository.ErrNotFound
+	}
+	if senderCoins < amount {
+		slog.DebugContext(ctx, "not enough coins", "coins", senderCoins, "amount", amount)
+		return nil, repository.ErrInsufficientCoins
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE users SET coins = coins - $1 WHERE id = $2", amount, fromID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, "UPDATE users SET coins = coins + $1 WHERE id = $2", amount, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := model.CoinTransaction{FromID: fromID, ToID: toID, Amount: amount}
+	err = tx.QueryRow(ctx,
+		"INSERT INTO coin_transactions (from_id, to_id, amount) VALUES ($1, $2, $3) RETURNING id, created_at",
+		fromID, toID, amount).Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func (r *repo) ListHistory(ctx context.Context, userID uint) (*model.CoinHistory, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		`SELECT t.from_id, f.username, t.to_id, u.username, t.amount
+         FROM coin_transactions t
+         JOIN users f ON f.id = t.from_id
+         JOIN users u ON u.id = t.to_id
+         WHERE t.from_id = $1 OR t.to_id = $1
+         ORDER BY t.created_at DESC, t.id DESC`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := &model.CoinHistory{
+		Received: []model.ReceivedCoins{},
+		Sent:     []model.SentCoins{},
+	}
+	for rows.Next() {
+		var fromID, toID uint
+		var fromUser, toUser string
+		var amount int
+		if err := rows.Scan(&fromID, &fromUser, &toID, &toUser, &amount); err != nil {
+			return nil, err
+		}
+		if fromID == userID {
+			history.Sent = append(history.Sent, model.SentCoins{ToUser: toUser, Amount: amount})
+		} else {
+			history.Received = append(history.Received, model.ReceivedCoins{FromUser: fromUser, Amount: amount})
+		}
+	}
+	return history, rows.Err()
+}
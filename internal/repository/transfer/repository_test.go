@@ -0,0 +1,114 @@
+package transfer
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_TransferCoins(t *testing.T) {
+	createdAt := time.Now()
+
+	tests := []struct {
+		name        string
+		toUsername  string
+		mockSetup   func(m pgxmock.PgxPoolIface)
+		expected    *model.CoinTransaction
+		expectedErr error
+	}{
+		{
+			name:       "Успешный перевод",
+			toUsername: "bob",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(2)))
+				m.ExpectQuery("SELECT id, coins FROM users (.+) FOR UPDATE").WithArgs(uint(1), uint(2)).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "coins"}).AddRow(uint(1), 1000).AddRow(uint(2), 50))
+				m.ExpectExec("UPDATE users SET coins = coins - ").WithArgs(150, uint(1)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectExec("UPDATE users SET coins = coins \\+ ").WithArgs(150, uint(2)).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				m.ExpectQuery("INSERT INTO coin_transactions").WithArgs(uint(1), uint(2), 150).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(uint(9), createdAt))
+				m.ExpectCommit()
+			},
+			expected: &model.CoinTransaction{ID: 9, FromID: 1, ToID: 2, Amount: 150, CreatedAt: createdAt},
+		},
+		{
+			name:       "Получатель не найден",
+			toUsername: "nobody",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("nobody").
+					WillReturnError(pgx.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrRecipientNotFound,
+		},
+		{
+			name:       "Перевод самому себе",
+			toUsername: "alice",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("alice").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(1)))
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrSelfTransfer,
+		},
+		{
+			name:       "Не хватает монет",
+			toUsername: "bob",
+			mockSetup: func(m pgxmock.PgxPoolIface) {
+				m.ExpectBegin()
+				m.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uint(2)))
+				m.ExpectQuery("SELECT id, coins FROM users (.+) FOR UPDATE").WithArgs(uint(1), uint(2)).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "coins"}).AddRow(uint(1), 100).AddRow(uint(2), 50))
+				m.ExpectRollback()
+			},
+			expectedErr: repository.ErrInsufficientCoins,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.mockSetup(mock)
+
+			transaction, err := NewRepository(mock).TransferCoins(context.Background(), 1, tt.toUsername, 150)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, transaction)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListHistory(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("FROM coin_transactions").WithArgs(uint(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"from_id", "from", "to_id", "to", "amount"}).
+			AddRow(uint(2), "bob", uint(1), "alice", 30).
+			AddRow(uint(1), "alice", uint(3), "carol", 10))
+
+	history, err := NewRepository(mock).ListHistory(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []model.ReceivedCoins{{FromUser: "bob", Amount: 30}}, history.Received)
+	assert.Equal(t, []model.SentCoins{{ToUser: "carol", Amount: 10}}, history.Sent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
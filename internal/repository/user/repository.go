@@ -3,29 +3,27 @@ package user
 import (
 	"context"
 	"database/sql"
-	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/pkg/errors"
-	"log"
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"merchio/internal/client/db/pg"
 	"merchio/internal/model"
 	"merchio/internal/repository"
 )
 
+const uniqueViolation = "23505"
+
 type repo struct {
-	db *pgxpool.Pool
+	db pg.Querier
 }
 
-func NewRepository(ctx context.Context, dsn string) repository.UserRepository {
-	dbc, err := pgxpool.Connect(ctx, dsn)
-	if err != nil {
-		log.Fatal("Failed to connect to db")
-		errors.Errorf("failed to connect to db: %v", err)
-	}
-	return &repo{db: dbc}
+func NewRepository(db pg.Querier) repository.UserRepository {
+	return &repo{db: db}
 }
 
 func (r *repo) IsUserPresent(ctx context.Context, username string) (bool, error) {
 	var exists bool
-	err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE username=$1)", username).Scan(&exists)
+	err := pg.Conn(ctx, r.db).QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE username=$1)", username).Scan(&exists)
 	if err != nil && err != sql.ErrNoRows {
 		return false, err
 	}
@@ -34,9 +32,26 @@ func (r *repo) IsUserPresent(ctx context.Context, username string) (bool, error)
 
 func (r *repo) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.QueryRow(ctx,
-		"SELECT id, username, password, coins, created_at FROM users WHERE username = $1",
-		username).Scan(&user.ID, &user.Username, &user.Password, &user.Coins, &user.CreatedAt)
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		"SELECT id, username, password, coins, role, created_at FROM users WHERE username = $1",
+		username).Scan(&user.ID, &user.Username, &user.Password, &user.Coins, &user.Role, &user.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repo) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	var user model.User
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
+		"SELECT id, username, password, coins, role, created_at FROM users WHERE id = $1",
+		id).Scan(&user.ID, &user.Username, &user.Password, &user.Coins, &user.Role, &user.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -46,15 +61,30 @@ func (r *repo) GetUserByUsername(ctx context.Context, username string) (*model.U
 func (r *repo) CreateUser(ctx context.Context, username, password string) (int64, error) {
 	var id int64
 
-	err := r.db.QueryRow(ctx,
+	err := pg.Conn(ctx, r.db).QueryRow(ctx,
 		"INSERT INTO users (username, password, coins) VALUES ($1, $2, 1000) RETURNING id",
 		username, password).Scan(&id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return 0, repository.ErrAlreadyExists
+	}
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
+func (r *repo) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	tag, err := pg.Conn(ctx, r.db).Exec(ctx, "UPDATE users SET password = $2 WHERE id = $1", id, passwordHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
 //
 //func (r *repo) TransferCoins(ctx context.Context, fromID, toID uint, amount int) error {
 //	tx, err := r.db.BeginTx(ctx, nil)
@@ -95,55 +125,3 @@ func (r *repo) CreateUser(ctx context.Context, username, password string) (int64
 //
 //	return tx.Commit()
 //}
-//
-//func (r *repo) BuyMerch(ctx context.Context, userID uint, merchName string) error {
-//	tx, err := r.db.BeginTx(ctx, nil)
-//	if err != nil {
-//		return err
-//	}
-//	defer tx.Rollback()
-//
-//	// Получаем информацию о товаре
-//	var merchID uint
-//	var price int
-//	err = tx.QueryRowContext(ctx,
-//		"SELECT id, price FROM merch_items WHERE name = $1",
-//		merchName).Scan(&merchID, &price)
-//	if err != nil {
-//		return err
-//	}
-//
-//	// Проверяем баланс пользователя
-//	var userCoins int
-//	err = tx.QueryRowContext(ctx,
-//		"SELECT coins FROM users WHERE id = $1 FOR UPDATE",
-//		userID).Scan(&userCoins)
-//	if err != nil {
-//		return err
-//	}
-//
-//	if userCoins < price {
-//		return errors.New("insufficient coins")
-//	}
-//
-//	// Обновляем баланс
-//	_, err = tx.ExecContext(ctx,
-//		"UPDATE users SET coins = coins - $1 WHERE id = $2",
-//		price, userID)
-//	if err != nil {
-//		return err
-//	}
-//
-//	// Добавляем товар в инвентарь
-//	_, err = tx.ExecContext(ctx,
-//		`INSERT INTO user_inventory (user_id, merch_id, quantity)
-//         VALUES ($1, $2, 1)
-//         ON CONFLICT (user_id, merch_id)
-//         DO UPDATE SET quantity = user_inventory.quantity + 1`,
-//		userID, merchID)
-//	if err != nil {
-//		return err
-//	}
-//
-//	return tx.Commit()
-//}
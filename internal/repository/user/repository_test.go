@@ -2,19 +2,16 @@ package user
 
 import (
 	"context"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 	"merchio/internal/model"
+	"merchio/internal/repository"
 	"testing"
 	"time"
 )
 
-type mockPgxPool struct {
-	mock pgxmock.PgxPoolIface
-}
-
 func TestRepo_CreateUser(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -23,7 +20,7 @@ func TestRepo_CreateUser(t *testing.T) {
 	defer mock.Close()
 
 	repo := &repo{
-		db: &pgxpool.Pool{},
+		db: mock,
 	}
 	ctx := context.Background()
 
@@ -79,7 +76,7 @@ func TestRepo_GetUserByUsername(t *testing.T) {
 	defer mock.Close()
 
 	repo := &repo{
-		db: &pgxpool.Pool{},
+		db: mock,
 	}
 	ctx := context.Background()
 
@@ -88,6 +85,7 @@ func TestRepo_GetUserByUsername(t *testing.T) {
 		Username:  "testuser",
 		Password:  "hashedpass",
 		Coins:     1000,
+		Role:      model.RoleUser,
 		CreatedAt: time.Now(),
 	}
 
@@ -102,9 +100,9 @@ func TestRepo_GetUserByUsername(t *testing.T) {
 			name:     "Успешное получение пользователя",
 			username: "testuser",
 			mockSetup: func(m pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "username", "password", "coins", "created_at"}).
+				rows := pgxmock.NewRows([]string{"id", "username", "password", "coins", "role", "created_at"}).
 					AddRow(expectedUser.ID, expectedUser.Username, expectedUser.Password,
-						expectedUser.Coins, expectedUser.CreatedAt)
+						expectedUser.Coins, expectedUser.Role, expectedUser.CreatedAt)
 				m.ExpectQuery("SELECT (.+) FROM users WHERE").
 					WithArgs("testuser").
 					WillReturnRows(rows)
@@ -157,7 +155,7 @@ func TestRepo_IsUserPresent(t *testing.T) {
 	defer mock.Close()
 
 	repo := &repo{
-		db: &pgxpool.Pool{},
+		db: mock,
 	}
 	ctx := context.Background()
 
@@ -213,3 +211,80 @@ func TestRepo_IsUserPresent(t *testing.T) {
 		})
 	}
 }
+
+func TestRepo_CreateUser_AlreadyExists(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+	repo := &repo{db: mock}
+
+	mock.ExpectQuery("INSERT INTO users").
+		WithArgs("testuser", "password").
+		WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+
+	_, err = repo.CreateUser(context.Background(), "testuser", "password")
+
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetUserByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+	repo := &repo{db: mock}
+	ctx := context.Background()
+
+	createdAt := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE id").
+		WithArgs(7).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "username", "password", "coins", "role", "created_at"}).
+			AddRow(7, "admin", "hash", 500, model.RoleAdmin, createdAt))
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE id").
+		WithArgs(8).
+		WillReturnError(pgx.ErrNoRows)
+
+	user, err := repo.GetUserByID(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.User{ID: 7, Username: "admin", Password: "hash", Coins: 500, Role: model.RoleAdmin, CreatedAt: createdAt}, user)
+
+	_, err = repo.GetUserByID(ctx, 8)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_UpdatePassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		expectedErr error
+	}{
+		{name: "Пароль обновлён", affected: 1},
+		{name: "Пользователь не найден", affected: 0, expectedErr: repository.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+			repo := &repo{db: mock}
+
+			mock.ExpectExec("UPDATE users SET password").
+				WithArgs(1, "newhash").
+				WillReturnResult(pgxmock.NewResult("UPDATE", tt.affected))
+
+			err = repo.UpdatePassword(context.Background(), 1, "newhash")
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
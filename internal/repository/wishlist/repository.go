@@ -0,0 +1,78 @@
+package wishlist
+
+import (
+	"context"
+	"merchio/internal/client/db/pg"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+)
+
+type repo struct {
+	db pg.Querier
+}
+
+func NewRepository(db pg.Querier) repository.WishlistRepository {
+	return &repo{db: db}
+}
+
+func (r *repo) AddItem(ctx context.Context, userID, merchID uint) error {
+	_, err := pg.Conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO wishlist_items (user_id, merch_id) VALUES ($1, $2)
+         ON CONFLICT (user_id, merch_id) DO NOTHING`,
+		userID, merchID)
+	return err
+}
+
+func (r *repo) RemoveItem(ctx context.Context, userID, merchID uint) (bool, error) {
+	tag, err := pg.Conn(ctx, r.db).Exec(ctx,
+		"DELETE FROM wishlist_items WHERE user_id = $1 AND merch_id = $2",
+		userID, merchID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *repo) ListItems(ctx context.Context, userID uint) ([]model.WishlistEntry, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		`SELECT m.id, m.name, m.price, m.stock, w.created_at
+         FROM wishlist_items w
+         JOIN merch_items m ON m.id = w.merch_id
+         WHERE w.user_id = $1
+         ORDER BY w.created_at, m.name`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.WishlistEntry
+	for rows.Next() {
+		var e model.WishlistEntry
+		if err := rows.Scan(&e.Item.ID, &e.Item.Name, &e.Item.Price, &e.Item.Stock, &e.AddedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *repo) ListUserIDsByMerch(ctx context.Context, merchID uint) ([]uint, error) {
+	rows, err := pg.Conn(ctx, r.db).Query(ctx,
+		"SELECT user_id FROM wishlist_items WHERE merch_id = $1 ORDER BY user_id",
+		merchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
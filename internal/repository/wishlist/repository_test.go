@@ -0,0 +1,102 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"merchio/internal/model"
+)
+
+func TestRepo_AddItem(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	// Повторное добавление не ошибка: ON CONFLICT DO NOTHING
+	mock.ExpectExec(`INSERT INTO wishlist_items (.+) ON CONFLICT \(user_id, merch_id\) DO NOTHING`).
+		WithArgs(uint(1), uint(7)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	err = NewRepository(mock).AddItem(context.Background(), 1, 7)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_RemoveItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		dbErr    error
+		expected bool
+	}{
+		{name: "Товар был в списке", affected: 1, expected: true},
+		{name: "Товара не было в списке", affected: 0},
+		{name: "Ошибка БД", dbErr: errors.New("ошибка БД")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			exec := mock.ExpectExec("DELETE FROM wishlist_items").WithArgs(uint(1), uint(7))
+			if tt.dbErr != nil {
+				exec.WillReturnError(tt.dbErr)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("DELETE", tt.affected))
+			}
+
+			removed, err := NewRepository(mock).RemoveItem(context.Background(), 1, 7)
+
+			assert.ErrorIs(t, err, tt.dbErr)
+			assert.Equal(t, tt.expected, removed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_ListItems(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	addedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT m.id, m.name, m.price, m.stock, w.created_at FROM wishlist_items w").
+		WithArgs(uint(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "price", "stock", "created_at"}).
+			AddRow(uint(7), "hoody", 300, 0, addedAt).
+			AddRow(uint(2), "cup", 20, 100, addedAt.Add(time.Hour)))
+
+	entries, err := NewRepository(mock).ListItems(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.WishlistEntry{
+		{Item: model.MerchItem{ID: 7, Name: "hoody", Price: 300, Stock: 0}, AddedAt: addedAt},
+		{Item: model.MerchItem{ID: 2, Name: "cup", Price: 20, Stock: 100}, AddedAt: addedAt.Add(time.Hour)},
+	}, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_ListUserIDsByMerch(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT user_id FROM wishlist_items WHERE merch_id = \\$1").
+		WithArgs(uint(7)).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(uint(1)).AddRow(uint(3)))
+
+	ids, err := NewRepository(mock).ListUserIDsByMerch(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 3}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
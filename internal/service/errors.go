@@ -0,0 +1,49 @@
+package service
+
+import "errors"
+
+// Kind - класс доменной ошибки, по нему транспорт выбирает код ответа
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindInsufficientFunds
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindTooManyRequests
+)
+
+// Error - ошибка, текст которой можно показать клиенту
+type Error struct {
+	Kind    Kind
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NewError(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func Validation(message string) *Error   { return NewError(KindValidation, message) }
+func NotFound(message string) *Error     { return NewError(KindNotFound, message) }
+func Conflict(message string) *Error     { return NewError(KindConflict, message) }
+func Unauthorized(message string) *Error { return NewError(KindUnauthorized, message) }
+
+// KindOf возвращает класс ошибки, всё, что не *Error и не FieldErrors, считается внутренней ошибкой
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	var fields FieldErrors
+	if errors.As(err, &fields) {
+		return KindValidation
+	}
+	return KindInternal
+}
@@ -0,0 +1,83 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"merchio/internal/metrics"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"strings"
+)
+
+func (s *serv) BuyItem(ctx context.Context, req model.PurchaseRequest) (_ *model.Purchase, err error) {
+	ctx, span := tracing.Start(ctx, "inventory.BuyItem",
+		attribute.String("merch.item", req.Item), attribute.String("merch.sku", req.SKU))
+	defer tracing.End(span, &err)
+
+	req.Coupon = strings.ToUpper(strings.TrimSpace(req.Coupon))
+	req.Recipient = strings.TrimSpace(req.Recipient)
+
+	var purchase *model.Purchase
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		purchase, err = s.inventoryRepository.BuyMerch(ctx, req)
+		if err != nil || purchase.RecipientID == nil {
+			return err
+		}
+		// Уведомление о подарке пишется в той же транзакции: если оно не сохранилось, покупка откатывается
+		return s.notifyRecipient(ctx, purchase)
+	})
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, service.ErrItemNotFound
+	case errors.Is(err, repository.ErrVariantNotFound):
+		return nil, service.ErrVariantNotFound
+	case errors.Is(err, repository.ErrUserNotFound):
+		return nil, service.ErrUserNotFound
+	case errors.Is(err, repository.ErrRecipientNotFound):
+		return nil, service.ErrRecipientNotFound
+	case errors.Is(err, repository.ErrVariantRequired):
+		return nil, service.ErrVariantRequired
+	case errors.Is(err, repository.ErrOutOfStock):
+		return nil, service.ErrOutOfStock
+	case errors.Is(err, repository.ErrInsufficientCoins):
+		return nil, service.ErrInsufficientCoins
+	case errors.Is(err, repository.ErrCouponInvalid):
+		return nil, service.ErrCouponInvalid
+	case errors.Is(err, repository.ErrCouponNotApplies):
+		return nil, service.ErrCouponNotApplicable
+	case errors.Is(err, repository.ErrCouponUsed):
+		return nil, service.ErrCouponUsed
+	case err != nil:
+		slog.ErrorContext(ctx, "buy merch failed", "item", req.Item, "sku", req.SKU, "error", err)
+		return nil, err
+	}
+	metrics.ObservePurchase(purchase.Item, purchase.Price)
+	slog.InfoContext(ctx, "purchase completed",
+		"order_id", purchase.OrderID, "item", purchase.Item, "sku", purchase.SKU,
+		"price", purchase.Price, "discount", purchase.Discount, "gift", purchase.RecipientID != nil)
+	return purchase, nil
+}
+
+// notifyRecipient сообщает получателю подарка, от кого он
+func (s *serv) notifyRecipient(ctx context.Context, purchase *model.Purchase) error {
+	buyer, err := s.userRepository.GetUserByID(ctx, int(purchase.UserID))
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.notificationRepository.CreateNotifications(ctx, []model.Notification{{
+		UserID:  *purchase.RecipientID,
+		Kind:    model.NotificationGift,
+		MerchID: purchase.MerchID,
+		Message: fmt.Sprintf("%s sent you a gift: %s", buyer.Username, purchase.Item),
+	}})
+}
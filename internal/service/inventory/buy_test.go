@@ -0,0 +1,302 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) IsUserPresent(ctx context.Context, username string) (bool, error) {
+	args := m.Called(ctx, username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, username, password string) (int64, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	args := m.Called(ctx, username)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	return m.Called(ctx, id, passwordHash).Error(0)
+}
+
+type MockInventoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) BuyMerch(ctx context.Context, req model.PurchaseRequest) (*model.Purchase, error) {
+	args := m.Called(ctx, req)
+	purchase, _ := args.Get(0).(*model.Purchase)
+	return purchase, args.Error(1)
+}
+
+func (m *MockInventoryRepository) ListInventory(ctx context.Context, userID uint) ([]model.InventoryItem, error) {
+	args := m.Called(ctx, userID)
+	items, _ := args.Get(0).([]model.InventoryItem)
+	return items, args.Error(1)
+}
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) TransferCoins(ctx context.Context, fromID uint, toUsername string, amount int) (*model.CoinTransaction, error) {
+	args := m.Called(ctx, fromID, toUsername, amount)
+	transaction, _ := args.Get(0).(*model.CoinTransaction)
+	return transaction, args.Error(1)
+}
+
+func (m *MockTransferRepository) ListHistory(ctx context.Context, userID uint) (*model.CoinHistory, error) {
+	args := m.Called(ctx, userID)
+	history, _ := args.Get(0).(*model.CoinHistory)
+	return history, args.Error(1)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []model.Notification) error {
+	args := m.Called(ctx, notifications)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ListByUser(ctx context.Context, userID uint, unreadOnly bool) ([]model.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly)
+	notifications, _ := args.Get(0).([]model.Notification)
+	return notifications, args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id uint) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockTxManager выполняет fn без транзакции и запоминает, с какой ошибкой она закончилась
+type MockTxManager struct {
+	calls int
+	err   error
+}
+
+func (m *MockTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.err = fn(ctx)
+	return m.err
+}
+
+func TestBuyItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		sku         string
+		coupon      string
+		repoResult  *model.Purchase
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:       "Успешная покупка варианта",
+			sku:        "HOODY-GRY-M",
+			repoResult: &model.Purchase{UserID: 1, Item: "hoody", SKU: "HOODY-GRY-M", Price: 300, Balance: 700},
+		},
+		{
+			name:        "Вариант не указан",
+			repoErr:     repository.ErrVariantRequired,
+			expectedErr: service.ErrVariantRequired,
+		},
+		{
+			name:        "Неизвестный товар",
+			repoErr:     repository.ErrNotFound,
+			expectedErr: service.ErrItemNotFound,
+		},
+		{
+			name:        "Неизвестный вариант",
+			sku:         "HOODY-GRY-XXXL",
+			repoErr:     repository.ErrVariantNotFound,
+			expectedErr: service.ErrVariantNotFound,
+		},
+		{
+			name:        "Покупатель удалён",
+			sku:         "HOODY-GRY-M",
+			repoErr:     repository.ErrUserNotFound,
+			expectedErr: service.ErrUserNotFound,
+		},
+		{
+			name:        "Нет в наличии",
+			sku:         "HOODY-GRY-M",
+			repoErr:     repository.ErrOutOfStock,
+			expectedErr: service.ErrOutOfStock,
+		},
+		{
+			name:        "Не хватает монет",
+			sku:         "HOODY-GRY-M",
+			repoErr:     repository.ErrInsufficientCoins,
+			expectedErr: service.ErrInsufficientCoins,
+		},
+		{
+			name:        "Неизвестный получатель подарка",
+			sku:         "HOODY-GRY-M",
+			repoErr:     repository.ErrRecipientNotFound,
+			expectedErr: service.ErrRecipientNotFound,
+		},
+		{
+			name:        "Неизвестный купон",
+			sku:         "HOODY-GRY-M",
+			coupon:      "NOPE",
+			repoErr:     repository.ErrCouponInvalid,
+			expectedErr: service.ErrCouponInvalid,
+		},
+		{
+			name:        "Купон на другой товар",
+			sku:         "HOODY-GRY-M",
+			coupon:      "CUPS10",
+			repoErr:     repository.ErrCouponNotApplies,
+			expectedErr: service.ErrCouponNotApplicable,
+		},
+		{
+			name:        "Купон уже использован",
+			sku:         "HOODY-GRY-M",
+			coupon:      "ONCE",
+			repoErr:     repository.ErrCouponUsed,
+			expectedErr: service.ErrCouponUsed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventoryRepo := new(MockInventoryRepository)
+			s := NewService(new(MockUserRepository), inventoryRepo, new(MockTransferRepository), new(MockNotificationRepository), new(MockTxManager))
+
+			req := model.PurchaseRequest{UserID: 1, Item: "hoody", SKU: tt.sku, Coupon: tt.coupon}
+			inventoryRepo.On("BuyMerch", mock.Anything, req).Return(tt.repoResult, tt.repoErr)
+
+			purchase, err := s.BuyItem(context.Background(), req)
+
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				assert.Nil(t, purchase)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.repoResult, purchase)
+			}
+			inventoryRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBuyItem_NormalizesCoupon(t *testing.T) {
+	inventoryRepo := new(MockInventoryRepository)
+	s := NewService(new(MockUserRepository), inventoryRepo, new(MockTransferRepository), new(MockNotificationRepository), new(MockTxManager))
+
+	expected := model.PurchaseRequest{UserID: 1, Item: "cup", Coupon: "SPRING20"}
+	inventoryRepo.On("BuyMerch", mock.Anything, expected).
+		Return(&model.Purchase{Item: "cup", ListPrice: 20, Discount: 4, Coupon: "SPRING20", Price: 16}, nil)
+
+	purchase, err := s.BuyItem(context.Background(), model.PurchaseRequest{UserID: 1, Item: "cup", Coupon: " spring20 "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 16, purchase.Price)
+	inventoryRepo.AssertExpectations(t)
+}
+
+func TestBuyItem_GiftNotifiesRecipient(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	inventoryRepo := new(MockInventoryRepository)
+	notificationRepo := new(MockNotificationRepository)
+	s := NewService(userRepo, inventoryRepo, new(MockTransferRepository), notificationRepo, new(MockTxManager))
+
+	recipientID := uint(2)
+	req := model.PurchaseRequest{UserID: 1, Item: "cup", Recipient: "bob"}
+	inventoryRepo.On("BuyMerch", mock.Anything, req).Return(&model.Purchase{
+		OrderID: 9, UserID: 1, RecipientID: &recipientID, Recipient: "bob", Item: "cup", MerchID: 2,
+		ListPrice: 20, Price: 20, Balance: 980,
+	}, nil)
+	userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Username: "alice"}, nil)
+	notificationRepo.On("CreateNotifications", mock.Anything, []model.Notification{{
+		UserID:  2,
+		Kind:    model.NotificationGift,
+		MerchID: 2,
+		Message: "alice sent you a gift: cup",
+	}}).Return(nil)
+
+	purchase, err := s.BuyItem(context.Background(), model.PurchaseRequest{UserID: 1, Item: "cup", Recipient: " bob "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", purchase.Recipient)
+	assert.Equal(t, 980, purchase.Balance)
+	inventoryRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestBuyItem_GiftNotificationFailureRollsBack(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	inventoryRepo := new(MockInventoryRepository)
+	notificationRepo := new(MockNotificationRepository)
+	txManager := new(MockTxManager)
+	s := NewService(userRepo, inventoryRepo, new(MockTransferRepository), notificationRepo, txManager)
+
+	recipientID := uint(2)
+	req := model.PurchaseRequest{UserID: 1, Item: "cup", Recipient: "bob"}
+	inventoryRepo.On("BuyMerch", mock.Anything, req).Return(&model.Purchase{UserID: 1, RecipientID: &recipientID, Item: "cup"}, nil)
+	userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Username: "alice"}, nil)
+	dbErr := errors.New("ошибка БД")
+	notificationRepo.On("CreateNotifications", mock.Anything, mock.Anything).Return(dbErr)
+
+	purchase, err := s.BuyItem(context.Background(), req)
+
+	// Ошибка вернулась из транзакции, значит покупка откатилась вместе с уведомлением
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, purchase)
+	assert.Equal(t, 1, txManager.calls)
+	assert.ErrorIs(t, txManager.err, dbErr)
+}
+
+func TestBuyItem_RecordsSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracing.NewProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	inventoryRepo := new(MockInventoryRepository)
+	s := NewService(new(MockUserRepository), inventoryRepo, new(MockTransferRepository), new(MockNotificationRepository), new(MockTxManager))
+	req := model.PurchaseRequest{UserID: 1, Item: "pink-hoody"}
+	inventoryRepo.On("BuyMerch", mock.Anything, req).Return(nil, repository.ErrInsufficientCoins)
+
+	_, err := s.BuyItem(context.Background(), req)
+
+	assert.ErrorIs(t, err, service.ErrInsufficientCoins)
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "inventory.BuyItem", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("merch.item", "pink-hoody"))
+	}
+}
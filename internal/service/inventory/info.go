@@ -0,0 +1,36 @@
+package inventory
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) GetInfo(ctx context.Context, userID int) (_ *model.UserInfo, err error) {
+	ctx, span := tracing.Start(ctx, "inventory.GetInfo")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory, err := s.inventoryRepository.ListInventory(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+	if inventory == nil {
+		inventory = []model.InventoryItem{}
+	}
+
+	history, err := s.transferRepository.ListHistory(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserInfo{
+		Coins:       user.Coins,
+		Inventory:   inventory,
+		CoinHistory: *history,
+	}, nil
+}
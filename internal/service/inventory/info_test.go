@@ -0,0 +1,59 @@
+package inventory
+
+import (
+	"context"
+	"merchio/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetInfo(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	inventoryRepo := new(MockInventoryRepository)
+	transferRepo := new(MockTransferRepository)
+	s := NewService(userRepo, inventoryRepo, transferRepo, new(MockNotificationRepository), new(MockTxManager))
+
+	userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Coins: 620}, nil)
+	transferRepo.On("ListHistory", mock.Anything, uint(1)).Return(&model.CoinHistory{
+		Received: []model.ReceivedCoins{{FromUser: "alice", Amount: 100}},
+		Sent:     []model.SentCoins{{ToUser: "bob", Amount: 40}, {ToUser: "carol", Amount: 10}},
+	}, nil)
+	inventoryRepo.On("ListInventory", mock.Anything, uint(1)).Return([]model.InventoryItem{
+		{Type: "cup", Quantity: 2},
+		{Type: "hoody", Quantity: 1, SKU: "HOODY-GRY-M", Size: "M", Color: "grey"},
+	}, nil)
+
+	info, err := s.GetInfo(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 620, info.Coins)
+	assert.Len(t, info.Inventory, 2)
+	assert.Equal(t, "HOODY-GRY-M", info.Inventory[1].SKU)
+	assert.Equal(t, []model.ReceivedCoins{{FromUser: "alice", Amount: 100}}, info.CoinHistory.Received)
+	assert.Len(t, info.CoinHistory.Sent, 2)
+	userRepo.AssertExpectations(t)
+	inventoryRepo.AssertExpectations(t)
+	transferRepo.AssertExpectations(t)
+}
+
+func TestGetInfo_EmptyInventory(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	inventoryRepo := new(MockInventoryRepository)
+	transferRepo := new(MockTransferRepository)
+	s := NewService(userRepo, inventoryRepo, transferRepo, new(MockNotificationRepository), new(MockTxManager))
+
+	userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Coins: 1000}, nil)
+	inventoryRepo.On("ListInventory", mock.Anything, uint(1)).Return(nil, nil)
+	transferRepo.On("ListHistory", mock.Anything, uint(1)).Return(&model.CoinHistory{
+		Received: []model.ReceivedCoins{},
+		Sent:     []model.SentCoins{},
+	}, nil)
+
+	info, err := s.GetInfo(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, info.Inventory)
+	assert.Empty(t, info.Inventory)
+}
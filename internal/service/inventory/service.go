@@ -0,0 +1,30 @@
+package inventory
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	userRepository         repository.UserRepository
+	inventoryRepository    repository.InventoryRepository
+	transferRepository     repository.TransferRepository
+	notificationRepository repository.NotificationRepository
+	txManager              repository.TxManager
+}
+
+func NewService(
+	userRepository repository.UserRepository,
+	inventoryRepository repository.InventoryRepository,
+	transferRepository repository.TransferRepository,
+	notificationRepository repository.NotificationRepository,
+	txManager repository.TxManager,
+) service.InventoryService {
+	return &serv{
+		userRepository:         userRepository,
+		inventoryRepository:    inventoryRepository,
+		transferRepository:     transferRepository,
+		notificationRepository: notificationRepository,
+		txManager:              txManager,
+	}
+}
@@ -0,0 +1,31 @@
+package merch
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) ListItems(ctx context.Context) (_ []model.MerchItem, err error) {
+	ctx, span := tracing.Start(ctx, "merch.ListItems")
+	defer tracing.End(span, &err)
+
+	items, err := s.merchRepository.ListItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.merchRepository.ListVariants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byItem := make(map[uint][]model.MerchVariant)
+	for _, v := range variants {
+		byItem[v.MerchID] = append(byItem[v.MerchID], v)
+	}
+	for i := range items {
+		items[i].Variants = byItem[items[i].ID]
+	}
+	return items, nil
+}
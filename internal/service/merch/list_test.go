@@ -0,0 +1,32 @@
+package merch
+
+import (
+	"context"
+	"merchio/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListItems_GroupsVariants(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	s := NewService(merchRepo, new(MockWishlistRepository), new(MockNotificationRepository), new(MockTxManager))
+
+	merchRepo.On("ListItems", mock.Anything).Return([]model.MerchItem{
+		{ID: 2, Name: "cup", Price: 20, Stock: 10},
+		{ID: 6, Name: "hoody", Price: 300},
+	}, nil)
+	merchRepo.On("ListVariants", mock.Anything).Return([]model.MerchVariant{
+		{ID: 1, MerchID: 6, SKU: "HOODY-GRY-M", Size: "M"},
+		{ID: 2, MerchID: 6, SKU: "HOODY-GRY-L", Size: "L"},
+	}, nil)
+
+	items, err := s.ListItems(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Empty(t, items[0].Variants)
+	assert.Len(t, items[1].Variants, 2)
+	merchRepo.AssertExpectations(t)
+}
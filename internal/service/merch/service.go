@@ -0,0 +1,27 @@
+package merch
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	merchRepository        repository.MerchRepository
+	wishlistRepository     repository.WishlistRepository
+	notificationRepository repository.NotificationRepository
+	txManager              repository.TxManager
+}
+
+func NewService(
+	merchRepository repository.MerchRepository,
+	wishlistRepository repository.WishlistRepository,
+	notificationRepository repository.NotificationRepository,
+	txManager repository.TxManager,
+) service.MerchService {
+	return &serv{
+		merchRepository:        merchRepository,
+		wishlistRepository:     wishlistRepository,
+		notificationRepository: notificationRepository,
+		txManager:              txManager,
+	}
+}
@@ -0,0 +1,133 @@
+package merch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"strings"
+)
+
+func (s *serv) UpdateItem(ctx context.Context, name string, price, stock int) (_ *model.MerchItem, err error) {
+	ctx, span := tracing.Start(ctx, "merch.UpdateItem")
+	defer tracing.End(span, &err)
+
+	if price <= 0 || stock < 0 {
+		return nil, service.ErrInvalidItemUpdate
+	}
+
+	// Уведомления пишутся в той же транзакции, что и изменение: иначе при их ошибке
+	// администратор получит 500 на уже сохранённое изменение
+	var after *model.MerchItem
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var before *model.MerchItem
+		var err error
+		before, after, err = s.merchRepository.UpdateItem(ctx, name, price, stock)
+		if err != nil {
+			return err
+		}
+
+		var kinds []string
+		if after.Price < before.Price {
+			kinds = append(kinds, model.NotificationPriceDrop)
+		}
+		// У товара с вариантами остаток в вариантах, о поступлении сообщает UpdateVariant
+		if !after.HasVariants && before.Stock == 0 && after.Stock > 0 {
+			kinds = append(kinds, model.NotificationBackInStock)
+		}
+
+		return s.notifyWishers(ctx, after.ID, kinds, func(kind string) string {
+			if kind == model.NotificationPriceDrop {
+				return fmt.Sprintf("%s is now %d coins instead of %d", after.Name, after.Price, before.Price)
+			}
+			return fmt.Sprintf("%s is back in stock", after.Name)
+		})
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, service.ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+func (s *serv) UpdateVariant(ctx context.Context, itemName string, variant model.MerchVariant) (_ *model.MerchVariant, err error) {
+	ctx, span := tracing.Start(ctx, "merch.UpdateVariant")
+	defer tracing.End(span, &err)
+
+	if variant.SKU == "" || variant.Stock < 0 || (variant.PriceOverride != nil && *variant.PriceOverride <= 0) {
+		return nil, service.ErrInvalidVariant
+	}
+
+	var after *model.MerchVariant
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var before *model.MerchVariant
+		var err error
+		before, after, err = s.merchRepository.UpsertVariant(ctx, itemName, variant)
+		if err != nil {
+			return err
+		}
+
+		var kinds []string
+		if before != nil && before.Stock == 0 && after.Stock > 0 {
+			kinds = append(kinds, model.NotificationBackInStock)
+		}
+
+		return s.notifyWishers(ctx, after.MerchID, kinds, func(string) string {
+			return fmt.Sprintf("%s (%s) is back in stock", itemName, variantLabel(after))
+		})
+	})
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, service.ErrItemNotFound
+	case errors.Is(err, repository.ErrSKUTaken):
+		return nil, service.ErrVariantSKUTaken
+	case err != nil:
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// notifyWishers создаёт по уведомлению каждого вида для всех, у кого товар в списке желаний
+func (s *serv) notifyWishers(ctx context.Context, merchID uint, kinds []string, message func(kind string) string) error {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	userIDs, err := s.wishlistRepository.ListUserIDsByMerch(ctx, merchID)
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]model.Notification, 0, len(userIDs)*len(kinds))
+	for _, userID := range userIDs {
+		for _, kind := range kinds {
+			notifications = append(notifications, model.Notification{
+				UserID:  userID,
+				Kind:    kind,
+				MerchID: merchID,
+				Message: message(kind),
+			})
+		}
+	}
+	return s.notificationRepository.CreateNotifications(ctx, notifications)
+}
+
+func variantLabel(v *model.MerchVariant) string {
+	var parts []string
+	for _, p := range []string{v.Size, v.Color} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return v.SKU
+	}
+	return strings.Join(parts, ", ")
+}
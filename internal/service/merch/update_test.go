@@ -0,0 +1,348 @@
+package merch
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMerchRepository struct {
+	mock.Mock
+}
+
+func (m *MockMerchRepository) GetItemByName(ctx context.Context, name string) (*model.MerchItem, error) {
+	args := m.Called(ctx, name)
+	item, _ := args.Get(0).(*model.MerchItem)
+	return item, args.Error(1)
+}
+
+func (m *MockMerchRepository) ListItems(ctx context.Context) ([]model.MerchItem, error) {
+	args := m.Called(ctx)
+	items, _ := args.Get(0).([]model.MerchItem)
+	return items, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpdateItem(ctx context.Context, name string, price, stock int) (*model.MerchItem, *model.MerchItem, error) {
+	args := m.Called(ctx, name, price, stock)
+	before, _ := args.Get(0).(*model.MerchItem)
+	after, _ := args.Get(1).(*model.MerchItem)
+	return before, after, args.Error(2)
+}
+
+func (m *MockMerchRepository) ListVariants(ctx context.Context) ([]model.MerchVariant, error) {
+	args := m.Called(ctx)
+	variants, _ := args.Get(0).([]model.MerchVariant)
+	return variants, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpsertVariant(ctx context.Context, itemName string, variant model.MerchVariant) (*model.MerchVariant, *model.MerchVariant, error) {
+	args := m.Called(ctx, itemName, variant)
+	before, _ := args.Get(0).(*model.MerchVariant)
+	after, _ := args.Get(1).(*model.MerchVariant)
+	return before, after, args.Error(2)
+}
+
+type MockWishlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockWishlistRepository) AddItem(ctx context.Context, userID, merchID uint) error {
+	args := m.Called(ctx, userID, merchID)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) RemoveItem(ctx context.Context, userID, merchID uint) (bool, error) {
+	args := m.Called(ctx, userID, merchID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWishlistRepository) ListItems(ctx context.Context, userID uint) ([]model.WishlistEntry, error) {
+	args := m.Called(ctx, userID)
+	entries, _ := args.Get(0).([]model.WishlistEntry)
+	return entries, args.Error(1)
+}
+
+func (m *MockWishlistRepository) ListUserIDsByMerch(ctx context.Context, merchID uint) ([]uint, error) {
+	args := m.Called(ctx, merchID)
+	ids, _ := args.Get(0).([]uint)
+	return ids, args.Error(1)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []model.Notification) error {
+	args := m.Called(ctx, notifications)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ListByUser(ctx context.Context, userID uint, unreadOnly bool) ([]model.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly)
+	notifications, _ := args.Get(0).([]model.Notification)
+	return notifications, args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id uint) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockTxManager выполняет fn без транзакции и запоминает, с какой ошибкой она закончилась
+type MockTxManager struct {
+	calls int
+	err   error
+}
+
+func (m *MockTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.err = fn(ctx)
+	return m.err
+}
+
+func TestUpdateItem(t *testing.T) {
+	tests := []struct {
+		name          string
+		price         int
+		stock         int
+		before        *model.MerchItem
+		repoErr       error
+		wishers       []uint
+		expectedKinds []string
+		expectedErr   error
+	}{
+		{
+			name:          "Снижение цены уведомляет подписчиков",
+			price:         250,
+			stock:         10,
+			before:        &model.MerchItem{ID: 6, Name: "hoody", Price: 300, Stock: 10},
+			wishers:       []uint{1, 2},
+			expectedKinds: []string{model.NotificationPriceDrop},
+		},
+		{
+			name:          "Товар снова в наличии",
+			price:         300,
+			stock:         5,
+			before:        &model.MerchItem{ID: 6, Name: "hoody", Price: 300, Stock: 0},
+			wishers:       []uint{1},
+			expectedKinds: []string{model.NotificationBackInStock},
+		},
+		{
+			name:          "Снижение цены и поступление одновременно",
+			price:         200,
+			stock:         5,
+			before:        &model.MerchItem{ID: 6, Name: "hoody", Price: 300, Stock: 0},
+			wishers:       []uint{1},
+			expectedKinds: []string{model.NotificationPriceDrop, model.NotificationBackInStock},
+		},
+		{
+			name:          "Остаток товара с вариантами не считается поступлением",
+			price:         250,
+			stock:         5,
+			before:        &model.MerchItem{ID: 6, Name: "hoody", Price: 300, Stock: 0, HasVariants: true},
+			wishers:       []uint{1},
+			expectedKinds: []string{model.NotificationPriceDrop},
+		},
+		{
+			name:   "Повышение цены без уведомлений",
+			price:  400,
+			stock:  10,
+			before: &model.MerchItem{ID: 6, Name: "hoody", Price: 300, Stock: 10},
+		},
+		{
+			name:        "Неизвестный товар",
+			price:       100,
+			stock:       1,
+			repoErr:     repository.ErrNotFound,
+			expectedErr: service.ErrItemNotFound,
+		},
+		{
+			name:        "Некорректная цена",
+			price:       0,
+			stock:       1,
+			expectedErr: service.ErrInvalidItemUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merchRepo := new(MockMerchRepository)
+			wishlistRepo := new(MockWishlistRepository)
+			notificationRepo := new(MockNotificationRepository)
+			s := NewService(merchRepo, wishlistRepo, notificationRepo, new(MockTxManager))
+
+			var after *model.MerchItem
+			if tt.before != nil {
+				after = &model.MerchItem{ID: tt.before.ID, Name: tt.before.Name, Price: tt.price, Stock: tt.stock,
+					HasVariants: tt.before.HasVariants}
+			}
+			if tt.price > 0 {
+				merchRepo.On("UpdateItem", mock.Anything, "hoody", tt.price, tt.stock).Return(tt.before, after, tt.repoErr)
+			}
+			if len(tt.expectedKinds) > 0 {
+				wishlistRepo.On("ListUserIDsByMerch", mock.Anything, uint(6)).Return(tt.wishers, nil)
+				notificationRepo.On("CreateNotifications", mock.Anything, mock.MatchedBy(func(ns []model.Notification) bool {
+					if len(ns) != len(tt.wishers)*len(tt.expectedKinds) {
+						return false
+					}
+					for i, n := range ns {
+						if n.UserID != tt.wishers[i/len(tt.expectedKinds)] ||
+							n.Kind != tt.expectedKinds[i%len(tt.expectedKinds)] ||
+							n.MerchID != 6 || n.Message == "" {
+							return false
+						}
+					}
+					return true
+				})).Return(nil)
+			}
+
+			item, err := s.UpdateItem(context.Background(), "hoody", tt.price, tt.stock)
+
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				assert.Nil(t, item)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, after, item)
+			}
+
+			merchRepo.AssertExpectations(t)
+			wishlistRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateVariant_BackInStock(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	notificationRepo := new(MockNotificationRepository)
+	s := NewService(merchRepo, wishlistRepo, notificationRepo, new(MockTxManager))
+
+	variant := model.MerchVariant{SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 3}
+	before := &model.MerchVariant{ID: 2, MerchID: 6, SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 0}
+	after := &model.MerchVariant{ID: 2, MerchID: 6, SKU: "HOODY-GRY-M", Size: "M", Color: "grey", Stock: 3}
+
+	merchRepo.On("UpsertVariant", mock.Anything, "hoody", variant).Return(before, after, nil)
+	wishlistRepo.On("ListUserIDsByMerch", mock.Anything, uint(6)).Return([]uint{4}, nil)
+	notificationRepo.On("CreateNotifications", mock.Anything, []model.Notification{{
+		UserID:  4,
+		Kind:    model.NotificationBackInStock,
+		MerchID: 6,
+		Message: "hoody (M, grey) is back in stock",
+	}}).Return(nil)
+
+	result, err := s.UpdateVariant(context.Background(), "hoody", variant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, after, result)
+	merchRepo.AssertExpectations(t)
+	wishlistRepo.AssertExpectations(t)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestUpdateVariant_NewVariantDoesNotNotify(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	notificationRepo := new(MockNotificationRepository)
+	s := NewService(merchRepo, wishlistRepo, notificationRepo, new(MockTxManager))
+
+	variant := model.MerchVariant{SKU: "HOODY-GRY-XXL", Size: "XXL", Stock: 5}
+	after := &model.MerchVariant{ID: 9, MerchID: 6, SKU: "HOODY-GRY-XXL", Size: "XXL", Stock: 5}
+
+	merchRepo.On("UpsertVariant", mock.Anything, "hoody", variant).Return(nil, after, nil)
+
+	result, err := s.UpdateVariant(context.Background(), "hoody", variant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, after, result)
+	wishlistRepo.AssertNotCalled(t, "ListUserIDsByMerch", mock.Anything, mock.Anything)
+	notificationRepo.AssertNotCalled(t, "CreateNotifications", mock.Anything, mock.Anything)
+}
+
+func TestUpdateVariant_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "Неизвестный товар", repoErr: repository.ErrNotFound, expectedErr: service.ErrItemNotFound},
+		{name: "SKU занят другим товаром", repoErr: repository.ErrSKUTaken, expectedErr: service.ErrVariantSKUTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merchRepo := new(MockMerchRepository)
+			s := NewService(merchRepo, new(MockWishlistRepository), new(MockNotificationRepository), new(MockTxManager))
+			variant := model.MerchVariant{SKU: "HOODY-GRY-M", Stock: 1}
+			merchRepo.On("UpsertVariant", mock.Anything, "cup", variant).Return(nil, nil, tt.repoErr)
+
+			result, err := s.UpdateVariant(context.Background(), "cup", variant)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestUpdateVariant_Invalid(t *testing.T) {
+	s := NewService(new(MockMerchRepository), new(MockWishlistRepository), new(MockNotificationRepository), new(MockTxManager))
+	zero := 0
+
+	_, err := s.UpdateVariant(context.Background(), "hoody", model.MerchVariant{SKU: "HOODY-GRY-M", PriceOverride: &zero})
+	assert.True(t, errors.Is(err, service.ErrInvalidVariant))
+
+	_, err = s.UpdateVariant(context.Background(), "hoody", model.MerchVariant{Stock: 1})
+	assert.True(t, errors.Is(err, service.ErrInvalidVariant))
+}
+
+func TestUpdateItem_NotificationFailureRollsBack(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	notificationRepo := new(MockNotificationRepository)
+	txManager := new(MockTxManager)
+	s := NewService(merchRepo, wishlistRepo, notificationRepo, txManager)
+
+	merchRepo.On("UpdateItem", mock.Anything, "hoody", 250, 10).Return(
+		&model.MerchItem{ID: 6, Name: "hoody", Price: 300, Stock: 10},
+		&model.MerchItem{ID: 6, Name: "hoody", Price: 250, Stock: 10}, nil)
+	wishlistRepo.On("ListUserIDsByMerch", mock.Anything, uint(6)).Return([]uint{1}, nil)
+	dbErr := errors.New("ошибка БД")
+	notificationRepo.On("CreateNotifications", mock.Anything, mock.Anything).Return(dbErr)
+
+	item, err := s.UpdateItem(context.Background(), "hoody", 250, 10)
+
+	// Ошибка вернулась из транзакции, значит изменение цены откатилось вместе с уведомлениями
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, item)
+	assert.Equal(t, 1, txManager.calls)
+	assert.ErrorIs(t, txManager.err, dbErr)
+}
+
+func TestUpdateVariant_NotificationFailureRollsBack(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	notificationRepo := new(MockNotificationRepository)
+	txManager := new(MockTxManager)
+	s := NewService(merchRepo, wishlistRepo, notificationRepo, txManager)
+
+	variant := model.MerchVariant{SKU: "HOODY-GRY-M", Size: "M", Stock: 3}
+	merchRepo.On("UpsertVariant", mock.Anything, "hoody", variant).Return(
+		&model.MerchVariant{ID: 2, MerchID: 6, SKU: "HOODY-GRY-M", Size: "M", Stock: 0},
+		&model.MerchVariant{ID: 2, MerchID: 6, SKU: "HOODY-GRY-M", Size: "M", Stock: 3}, nil)
+	wishlistRepo.On("ListUserIDsByMerch", mock.Anything, uint(6)).Return([]uint{4}, nil)
+	dbErr := errors.New("ошибка БД")
+	notificationRepo.On("CreateNotifications", mock.Anything, mock.Anything).Return(dbErr)
+
+	result, err := s.UpdateVariant(context.Background(), "hoody", variant)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, txManager.err, dbErr)
+}
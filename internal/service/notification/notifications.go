@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) ListNotifications(ctx context.Context, userID int, unreadOnly bool) (_ []model.Notification, err error) {
+	ctx, span := tracing.Start(ctx, "notification.ListNotifications")
+	defer tracing.End(span, &err)
+
+	return s.notificationRepository.ListByUser(ctx, uint(userID), unreadOnly)
+}
+
+func (s *serv) MarkRead(ctx context.Context, userID int, id uint) (err error) {
+	ctx, span := tracing.Start(ctx, "notification.MarkRead")
+	defer tracing.End(span, &err)
+
+	updated, err := s.notificationRepository.MarkRead(ctx, uint(userID), id)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return service.ErrNotificationNotFound
+	}
+	return nil
+}
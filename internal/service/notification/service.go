@@ -0,0 +1,16 @@
+package notification
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	notificationRepository repository.NotificationRepository
+}
+
+func NewService(notificationRepository repository.NotificationRepository) service.NotificationService {
+	return &serv{
+		notificationRepository: notificationRepository,
+	}
+}
@@ -0,0 +1,58 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) AdvanceOrder(ctx context.Context, id uint, pickupLocation string) (_ *model.OrderLine, err error) {
+	ctx, span := tracing.Start(ctx, "order.AdvanceOrder")
+	defer tracing.End(span, &err)
+
+	line, err := s.orderRepository.GetOrderLine(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, service.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	next, ok := model.NextFulfilmentStatus(line.Status)
+	if !ok {
+		return nil, service.ErrOrderDelivered
+	}
+	// Без места выдачи сотрудник не узнает, куда идти за товаром
+	if next == model.FulfilmentReadyForPickup && pickupLocation == "" && line.PickupLocation == "" {
+		return nil, service.ErrPickupLocationNeeded
+	}
+
+	// Уведомление пишется в той же транзакции, что и смена статуса: иначе при его ошибке
+	// клиент получит 500, а статус уже будет сохранён
+	var updated *model.OrderLine
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.orderRepository.UpdateStatus(ctx, id, line.Status, next, pickupLocation)
+		if err != nil || updated.Status != model.FulfilmentReadyForPickup {
+			return err
+		}
+		return s.notificationRepository.CreateNotifications(ctx, []model.Notification{{
+			UserID:  updated.OwnerID(),
+			Kind:    model.NotificationOrderReady,
+			MerchID: updated.MerchID,
+			Message: fmt.Sprintf("%s is ready for pickup at %s", updated.Item, updated.PickupLocation),
+		}})
+	})
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return nil, service.ErrOrderStatusConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,210 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) GetOrderLine(ctx context.Context, id uint) (*model.OrderLine, error) {
+	args := m.Called(ctx, id)
+	line, _ := args.Get(0).(*model.OrderLine)
+	return line, args.Error(1)
+}
+
+func (m *MockOrderRepository) ListByUser(ctx context.Context, userID uint) ([]model.OrderLine, error) {
+	args := m.Called(ctx, userID)
+	lines, _ := args.Get(0).([]model.OrderLine)
+	return lines, args.Error(1)
+}
+
+func (m *MockOrderRepository) ListByStatus(ctx context.Context, status string) ([]model.OrderLine, error) {
+	args := m.Called(ctx, status)
+	lines, _ := args.Get(0).([]model.OrderLine)
+	return lines, args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id uint, from, to, pickupLocation string) (*model.OrderLine, error) {
+	args := m.Called(ctx, id, from, to, pickupLocation)
+	line, _ := args.Get(0).(*model.OrderLine)
+	return line, args.Error(1)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []model.Notification) error {
+	args := m.Called(ctx, notifications)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ListByUser(ctx context.Context, userID uint, unreadOnly bool) ([]model.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly)
+	notifications, _ := args.Get(0).([]model.Notification)
+	return notifications, args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id uint) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockTxManager выполняет fn без транзакции и запоминает, с какой ошибкой она закончилась
+type MockTxManager struct {
+	calls int
+	err   error
+}
+
+func (m *MockTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.err = fn(ctx)
+	return m.err
+}
+
+func TestAdvanceOrder_PlacedToPacked(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	notificationRepo := new(MockNotificationRepository)
+	s := NewService(orderRepo, notificationRepo, new(MockTxManager))
+
+	orderRepo.On("GetOrderLine", mock.Anything, uint(5)).Return(&model.OrderLine{ID: 5, Status: model.FulfilmentPlaced}, nil)
+	orderRepo.On("UpdateStatus", mock.Anything, uint(5), model.FulfilmentPlaced, model.FulfilmentPacked, "").
+		Return(&model.OrderLine{ID: 5, Status: model.FulfilmentPacked}, nil)
+
+	line, err := s.AdvanceOrder(context.Background(), 5, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.FulfilmentPacked, line.Status)
+	orderRepo.AssertExpectations(t)
+	notificationRepo.AssertNotCalled(t, "CreateNotifications", mock.Anything, mock.Anything)
+}
+
+func TestAdvanceOrder_ReadyForPickupNotifiesUser(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	notificationRepo := new(MockNotificationRepository)
+	s := NewService(orderRepo, notificationRepo, new(MockTxManager))
+
+	orderRepo.On("GetOrderLine", mock.Anything, uint(5)).
+		Return(&model.OrderLine{ID: 5, UserID: 3, MerchID: 6, Item: "hoody", Status: model.FulfilmentPacked}, nil)
+	orderRepo.On("UpdateStatus", mock.Anything, uint(5), model.FulfilmentPacked, model.FulfilmentReadyForPickup, "Reception, 3rd floor").
+		Return(&model.OrderLine{ID: 5, UserID: 3, MerchID: 6, Item: "hoody", Status: model.FulfilmentReadyForPickup, PickupLocation: "Reception, 3rd floor"}, nil)
+	notificationRepo.On("CreateNotifications", mock.Anything, []model.Notification{{
+		UserID:  3,
+		Kind:    model.NotificationOrderReady,
+		MerchID: 6,
+		Message: "hoody is ready for pickup at Reception, 3rd floor",
+	}}).Return(nil)
+
+	line, err := s.AdvanceOrder(context.Background(), 5, "Reception, 3rd floor")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.FulfilmentReadyForPickup, line.Status)
+	orderRepo.AssertExpectations(t)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestAdvanceOrder_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        *model.OrderLine
+		getErr      error
+		updateErr   error
+		pickup      string
+		expectedErr error
+	}{
+		{
+			name:        "Строка заказа не найдена",
+			getErr:      repository.ErrNotFound,
+			expectedErr: service.ErrOrderNotFound,
+		},
+		{
+			name:        "Уже выдано",
+			line:        &model.OrderLine{ID: 5, Status: model.FulfilmentDelivered},
+			expectedErr: service.ErrOrderDelivered,
+		},
+		{
+			name:        "Нет места выдачи",
+			line:        &model.OrderLine{ID: 5, Status: model.FulfilmentPacked},
+			expectedErr: service.ErrPickupLocationNeeded,
+		},
+		{
+			name:        "Статус изменили параллельно",
+			line:        &model.OrderLine{ID: 5, Status: model.FulfilmentPlaced},
+			updateErr:   repository.ErrStatusChanged,
+			expectedErr: service.ErrOrderStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			s := NewService(orderRepo, new(MockNotificationRepository), new(MockTxManager))
+
+			orderRepo.On("GetOrderLine", mock.Anything, uint(5)).Return(tt.line, tt.getErr)
+			if tt.updateErr != nil {
+				orderRepo.On("UpdateStatus", mock.Anything, uint(5), mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.updateErr)
+			}
+
+			line, err := s.AdvanceOrder(context.Background(), 5, tt.pickup)
+
+			assert.True(t, errors.Is(err, tt.expectedErr))
+			assert.Nil(t, line)
+			orderRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdvanceOrder_GiftReadyNotifiesRecipient(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	notificationRepo := new(MockNotificationRepository)
+	s := NewService(orderRepo, notificationRepo, new(MockTxManager))
+
+	recipientID := uint(8)
+	orderRepo.On("GetOrderLine", mock.Anything, uint(5)).
+		Return(&model.OrderLine{ID: 5, UserID: 3, RecipientID: &recipientID, MerchID: 2, Item: "cup", Status: model.FulfilmentPacked, PickupLocation: "Reception"}, nil)
+	orderRepo.On("UpdateStatus", mock.Anything, uint(5), model.FulfilmentPacked, model.FulfilmentReadyForPickup, "").
+		Return(&model.OrderLine{ID: 5, UserID: 3, RecipientID: &recipientID, MerchID: 2, Item: "cup", Status: model.FulfilmentReadyForPickup, PickupLocation: "Reception"}, nil)
+	notificationRepo.On("CreateNotifications", mock.Anything, []model.Notification{{
+		UserID:  8,
+		Kind:    model.NotificationOrderReady,
+		MerchID: 2,
+		Message: "cup is ready for pickup at Reception",
+	}}).Return(nil)
+
+	_, err := s.AdvanceOrder(context.Background(), 5, "")
+
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestAdvanceOrder_NotificationFailureRollsBack(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	notificationRepo := new(MockNotificationRepository)
+	txManager := new(MockTxManager)
+	s := NewService(orderRepo, notificationRepo, txManager)
+
+	orderRepo.On("GetOrderLine", mock.Anything, uint(5)).
+		Return(&model.OrderLine{ID: 5, UserID: 3, Item: "hoody", Status: model.FulfilmentPacked}, nil)
+	orderRepo.On("UpdateStatus", mock.Anything, uint(5), model.FulfilmentPacked, model.FulfilmentReadyForPickup, "Reception").
+		Return(&model.OrderLine{ID: 5, UserID: 3, Item: "hoody", Status: model.FulfilmentReadyForPickup, PickupLocation: "Reception"}, nil)
+	dbErr := errors.New("ошибка БД")
+	notificationRepo.On("CreateNotifications", mock.Anything, mock.Anything).Return(dbErr)
+
+	line, err := s.AdvanceOrder(context.Background(), 5, "Reception")
+
+	// Ошибка вернулась из транзакции, значит смена статуса откатилась вместе с уведомлением
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, line)
+	assert.Equal(t, 1, txManager.calls)
+	assert.ErrorIs(t, txManager.err, dbErr)
+}
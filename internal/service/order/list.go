@@ -0,0 +1,40 @@
+package order
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) ListOrders(ctx context.Context, userID int) (_ []model.OrderLine, err error) {
+	ctx, span := tracing.Start(ctx, "order.ListOrders")
+	defer tracing.End(span, &err)
+
+	lines, err := s.orderRepository.ListByUser(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		lines = []model.OrderLine{}
+	}
+	return lines, nil
+}
+
+func (s *serv) ListAllOrders(ctx context.Context, status string) (_ []model.OrderLine, err error) {
+	ctx, span := tracing.Start(ctx, "order.ListAllOrders")
+	defer tracing.End(span, &err)
+
+	if status != "" && !model.IsFulfilmentStatus(status) {
+		return nil, service.ErrInvalidStatus
+	}
+
+	lines, err := s.orderRepository.ListByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		lines = []model.OrderLine{}
+	}
+	return lines, nil
+}
@@ -0,0 +1,47 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListOrders_Empty(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	s := NewService(orderRepo, new(MockNotificationRepository), new(MockTxManager))
+
+	orderRepo.On("ListByUser", mock.Anything, uint(3)).Return(nil, nil)
+
+	lines, err := s.ListOrders(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, lines)
+	assert.Empty(t, lines)
+}
+
+func TestListAllOrders_FilterByStatus(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	s := NewService(orderRepo, new(MockNotificationRepository), new(MockTxManager))
+
+	orderRepo.On("ListByStatus", mock.Anything, model.FulfilmentPacked).
+		Return([]model.OrderLine{{ID: 1, Status: model.FulfilmentPacked}}, nil)
+
+	lines, err := s.ListAllOrders(context.Background(), model.FulfilmentPacked)
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 1)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestListAllOrders_UnknownStatus(t *testing.T) {
+	s := NewService(new(MockOrderRepository), new(MockNotificationRepository), new(MockTxManager))
+
+	_, err := s.ListAllOrders(context.Background(), "lost")
+
+	assert.True(t, errors.Is(err, service.ErrInvalidStatus))
+}
@@ -0,0 +1,24 @@
+package order
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	orderRepository        repository.OrderRepository
+	notificationRepository repository.NotificationRepository
+	txManager              repository.TxManager
+}
+
+func NewService(
+	orderRepository repository.OrderRepository,
+	notificationRepository repository.NotificationRepository,
+	txManager repository.TxManager,
+) service.OrderService {
+	return &serv{
+		orderRepository:        orderRepository,
+		notificationRepository: notificationRepository,
+		txManager:              txManager,
+	}
+}
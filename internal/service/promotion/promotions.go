@@ -0,0 +1,95 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"strings"
+)
+
+func (s *serv) CreatePromotion(ctx context.Context, itemName string, promotion model.Promotion) (_ *model.Promotion, err error) {
+	ctx, span := tracing.Start(ctx, "promotion.CreatePromotion")
+	defer tracing.End(span, &err)
+
+	if strings.TrimSpace(promotion.Name) == "" || (itemName == "" && promotion.Category == "") ||
+		!promotion.Discount.Valid() || !promotion.EndsAt.After(promotion.StartsAt) {
+		return nil, service.ErrInvalidPromotion
+	}
+
+	merchID, err := s.resolveItem(ctx, itemName)
+	if err != nil {
+		return nil, err
+	}
+	promotion.MerchID = merchID
+
+	return s.promotionRepository.CreatePromotion(ctx, promotion)
+}
+
+func (s *serv) ListPromotions(ctx context.Context) (_ []model.Promotion, err error) {
+	ctx, span := tracing.Start(ctx, "promotion.ListPromotions")
+	defer tracing.End(span, &err)
+
+	promotions, err := s.promotionRepository.ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if promotions == nil {
+		promotions = []model.Promotion{}
+	}
+	return promotions, nil
+}
+
+func (s *serv) CreateCoupon(ctx context.Context, itemName string, coupon model.Coupon) (_ *model.Coupon, err error) {
+	ctx, span := tracing.Start(ctx, "promotion.CreateCoupon")
+	defer tracing.End(span, &err)
+
+	coupon.Code = strings.ToUpper(strings.TrimSpace(coupon.Code))
+	if coupon.Code == "" || !coupon.Discount.Valid() || (coupon.MaxUses != nil && *coupon.MaxUses <= 0) ||
+		(coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt)) {
+		return nil, service.ErrInvalidCoupon
+	}
+
+	merchID, err := s.resolveItem(ctx, itemName)
+	if err != nil {
+		return nil, err
+	}
+	coupon.MerchID = merchID
+
+	created, err := s.promotionRepository.CreateCoupon(ctx, coupon)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, service.ErrCouponExists
+	}
+	return created, err
+}
+
+func (s *serv) ListCoupons(ctx context.Context) (_ []model.Coupon, err error) {
+	ctx, span := tracing.Start(ctx, "promotion.ListCoupons")
+	defer tracing.End(span, &err)
+
+	coupons, err := s.promotionRepository.ListCoupons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if coupons == nil {
+		coupons = []model.Coupon{}
+	}
+	return coupons, nil
+}
+
+// resolveItem возвращает id товара по имени, nil если скидка не привязана к товару
+func (s *serv) resolveItem(ctx context.Context, itemName string) (*uint, error) {
+	if itemName == "" {
+		return nil, nil
+	}
+	item, err := s.merchRepository.GetItemByName(ctx, itemName)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, service.ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item.ID, nil
+}
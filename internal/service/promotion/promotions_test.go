@@ -0,0 +1,177 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMerchRepository struct {
+	mock.Mock
+}
+
+func (m *MockMerchRepository) GetItemByName(ctx context.Context, name string) (*model.MerchItem, error) {
+	args := m.Called(ctx, name)
+	item, _ := args.Get(0).(*model.MerchItem)
+	return item, args.Error(1)
+}
+
+func (m *MockMerchRepository) ListItems(ctx context.Context) ([]model.MerchItem, error) {
+	args := m.Called(ctx)
+	items, _ := args.Get(0).([]model.MerchItem)
+	return items, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpdateItem(ctx context.Context, name string, price, stock int) (*model.MerchItem, *model.MerchItem, error) {
+	args := m.Called(ctx, name, price, stock)
+	before, _ := args.Get(0).(*model.MerchItem)
+	after, _ := args.Get(1).(*model.MerchItem)
+	return before, after, args.Error(2)
+}
+
+func (m *MockMerchRepository) ListVariants(ctx context.Context) ([]model.MerchVariant, error) {
+	args := m.Called(ctx)
+	variants, _ := args.Get(0).([]model.MerchVariant)
+	return variants, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpsertVariant(ctx context.Context, itemName string, variant model.MerchVariant) (*model.MerchVariant, *model.MerchVariant, error) {
+	args := m.Called(ctx, itemName, variant)
+	before, _ := args.Get(0).(*model.MerchVariant)
+	after, _ := args.Get(1).(*model.MerchVariant)
+	return before, after, args.Error(2)
+}
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) CreatePromotion(ctx context.Context, promotion model.Promotion) (*model.Promotion, error) {
+	args := m.Called(ctx, promotion)
+	p, _ := args.Get(0).(*model.Promotion)
+	return p, args.Error(1)
+}
+
+func (m *MockPromotionRepository) ListPromotions(ctx context.Context) ([]model.Promotion, error) {
+	args := m.Called(ctx)
+	promotions, _ := args.Get(0).([]model.Promotion)
+	return promotions, args.Error(1)
+}
+
+func (m *MockPromotionRepository) CreateCoupon(ctx context.Context, coupon model.Coupon) (*model.Coupon, error) {
+	args := m.Called(ctx, coupon)
+	c, _ := args.Get(0).(*model.Coupon)
+	return c, args.Error(1)
+}
+
+func (m *MockPromotionRepository) ListCoupons(ctx context.Context) ([]model.Coupon, error) {
+	args := m.Called(ctx)
+	coupons, _ := args.Get(0).([]model.Coupon)
+	return coupons, args.Error(1)
+}
+
+func TestCreatePromotion_ForItem(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	promotionRepo := new(MockPromotionRepository)
+	s := NewService(merchRepo, promotionRepo)
+
+	starts := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ends := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	merchID := uint(10)
+
+	merchRepo.On("GetItemByName", mock.Anything, "pink-hoody").Return(&model.MerchItem{ID: 10, Name: "pink-hoody"}, nil)
+	promotionRepo.On("CreatePromotion", mock.Anything, model.Promotion{
+		Name:     "pink march",
+		MerchID:  &merchID,
+		Discount: model.Discount{Kind: model.DiscountPercent, Value: 20},
+		StartsAt: starts,
+		EndsAt:   ends,
+	}).Return(&model.Promotion{ID: 1}, nil)
+
+	promotion, err := s.CreatePromotion(context.Background(), "pink-hoody", model.Promotion{
+		Name:     "pink march",
+		Discount: model.Discount{Kind: model.DiscountPercent, Value: 20},
+		StartsAt: starts,
+		EndsAt:   ends,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), promotion.ID)
+	merchRepo.AssertExpectations(t)
+	promotionRepo.AssertExpectations(t)
+}
+
+func TestCreatePromotion_Invalid(t *testing.T) {
+	s := NewService(new(MockMerchRepository), new(MockPromotionRepository))
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		item      string
+		promotion model.Promotion
+	}{
+		{
+			name:      "Без товара и категории",
+			promotion: model.Promotion{Name: "x", Discount: model.Discount{Kind: model.DiscountFixed, Value: 5}, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		},
+		{
+			name:      "Процент больше 100",
+			item:      "cup",
+			promotion: model.Promotion{Name: "x", Discount: model.Discount{Kind: model.DiscountPercent, Value: 120}, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		},
+		{
+			name:      "Окончание раньше начала",
+			promotion: model.Promotion{Name: "x", Category: "apparel", Discount: model.Discount{Kind: model.DiscountFixed, Value: 5}, StartsAt: now, EndsAt: now.Add(-time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreatePromotion(context.Background(), tt.item, tt.promotion)
+			assert.True(t, errors.Is(err, service.ErrInvalidPromotion))
+		})
+	}
+}
+
+func TestCreateCoupon_NormalizesCode(t *testing.T) {
+	promotionRepo := new(MockPromotionRepository)
+	s := NewService(new(MockMerchRepository), promotionRepo)
+	once := 1
+
+	promotionRepo.On("CreateCoupon", mock.Anything, model.Coupon{
+		Code:     "WELCOME",
+		Discount: model.Discount{Kind: model.DiscountFixed, Value: 50},
+		MaxUses:  &once,
+	}).Return(&model.Coupon{ID: 3, Code: "WELCOME"}, nil)
+
+	coupon, err := s.CreateCoupon(context.Background(), "", model.Coupon{
+		Code:     " welcome ",
+		Discount: model.Discount{Kind: model.DiscountFixed, Value: 50},
+		MaxUses:  &once,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "WELCOME", coupon.Code)
+	promotionRepo.AssertExpectations(t)
+}
+
+func TestCreateCoupon_Duplicate(t *testing.T) {
+	promotionRepo := new(MockPromotionRepository)
+	s := NewService(new(MockMerchRepository), promotionRepo)
+
+	promotionRepo.On("CreateCoupon", mock.Anything, mock.Anything).Return(nil, repository.ErrAlreadyExists)
+
+	_, err := s.CreateCoupon(context.Background(), "", model.Coupon{
+		Code:     "WELCOME",
+		Discount: model.Discount{Kind: model.DiscountPercent, Value: 10},
+	})
+
+	assert.True(t, errors.Is(err, service.ErrCouponExists))
+}
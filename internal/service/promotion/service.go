@@ -0,0 +1,21 @@
+package promotion
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	merchRepository     repository.MerchRepository
+	promotionRepository repository.PromotionRepository
+}
+
+func NewService(
+	merchRepository repository.MerchRepository,
+	promotionRepository repository.PromotionRepository,
+) service.PromotionService {
+	return &serv{
+		merchRepository:     merchRepository,
+		promotionRepository: promotionRepository,
+	}
+}
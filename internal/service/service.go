@@ -2,9 +2,89 @@ package service
 
 import (
 	"context"
+	"merchio/internal/model"
+)
+
+var (
+	ErrInvalidCredentials   = Unauthorized("invalid username or password")
+	ErrAccountLocked        = NewError(KindTooManyRequests, "too many failed login attempts, try again later")
+	ErrUserNotFound         = NotFound("user not found")
+	ErrResetTokenInvalid    = Validation("reset token is invalid, used or expired")
+	ErrItemNotFound         = NotFound("merch item not found")
+	ErrRecipientNotFound    = NotFound("gift recipient not found")
+	ErrNotificationNotFound = NotFound("notification not found")
+	ErrInvalidItemUpdate    = Validation("price must be positive and stock must not be negative")
+	ErrInvalidVariant       = Validation("variant sku is required, price override must be positive and stock must not be negative")
+	ErrVariantRequired      = Validation("item has variants, specify one")
+	ErrVariantNotFound      = NotFound("merch variant not found")
+	ErrVariantSKUTaken      = Conflict("variant sku already belongs to another item")
+	ErrOutOfStock           = Conflict("item is out of stock")
+	ErrInsufficientCoins    = NewError(KindInsufficientFunds, "insufficient coins")
+	ErrOrderNotFound        = NotFound("order line not found")
+	ErrOrderDelivered       = Conflict("order line is already delivered")
+	ErrOrderStatusConflict  = Conflict("order line status was changed by someone else, retry")
+	ErrPickupLocationNeeded = Validation("pickup location is required before the order is ready for pickup")
+	ErrInvalidStatus        = Validation("unknown fulfilment status")
+	ErrInvalidPromotion     = Validation("promotion needs a name, an item or category, a valid discount and ends_at after starts_at")
+	ErrInvalidCoupon        = Validation("coupon needs a code, a valid discount and a positive max_uses if set")
+	ErrCouponExists         = Conflict("coupon code already exists")
+	ErrCouponInvalid        = Validation("coupon is unknown or not active")
+	ErrCouponNotApplicable  = Validation("coupon does not apply to this item")
+	ErrCouponUsed           = Validation("coupon has already been used")
+	ErrReceiverNotFound     = NotFound("receiver not found")
+	ErrSelfTransfer         = Validation("cannot send coins to yourself")
+	ErrInvalidAmount        = Validation("amount must be positive")
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, username, password string) (int64, error)
-	Auth(ctx context.Context, username, password string) (string, error)
+	// Auth учитывает неудачные попытки по паре имя+IP и временно блокирует вход после серии неудач
+	Auth(ctx context.Context, username, password, ip string) (string, error)
+	// UnlockUser снимает блокировку входа, действие записывается в журнал от имени adminID
+	UnlockUser(ctx context.Context, adminID int, username string) (int64, error)
+	ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error
+	// IssueResetToken выдаёт одноразовый токен сброса пароля, прежние токены пользователя гасятся
+	IssueResetToken(ctx context.Context, adminID int, username string) (*model.PasswordResetToken, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type TransferService interface {
+	SendCoins(ctx context.Context, fromID int, toUser string, amount int) error
+}
+
+type MerchService interface {
+	// UpdateItem меняет цену и остаток товара и уведомляет тех, у кого он в списке желаний
+	UpdateItem(ctx context.Context, name string, price, stock int) (*model.MerchItem, error)
+	UpdateVariant(ctx context.Context, itemName string, variant model.MerchVariant) (*model.MerchVariant, error)
+	ListItems(ctx context.Context) ([]model.MerchItem, error)
+}
+
+type InventoryService interface {
+	BuyItem(ctx context.Context, req model.PurchaseRequest) (*model.Purchase, error)
+	GetInfo(ctx context.Context, userID int) (*model.UserInfo, error)
+}
+
+type WishlistService interface {
+	AddItem(ctx context.Context, userID int, itemName string) error
+	RemoveItem(ctx context.Context, userID int, itemName string) error
+	GetWishlist(ctx context.Context, userID int) (*model.Wishlist, error)
+}
+
+type NotificationService interface {
+	ListNotifications(ctx context.Context, userID int, unreadOnly bool) ([]model.Notification, error)
+	MarkRead(ctx context.Context, userID int, id uint) error
+}
+
+type OrderService interface {
+	ListOrders(ctx context.Context, userID int) ([]model.OrderLine, error)
+	ListAllOrders(ctx context.Context, status string) ([]model.OrderLine, error)
+	// AdvanceOrder переводит строку заказа в следующий статус выдачи
+	AdvanceOrder(ctx context.Context, id uint, pickupLocation string) (*model.OrderLine, error)
+}
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, itemName string, promotion model.Promotion) (*model.Promotion, error)
+	ListPromotions(ctx context.Context) ([]model.Promotion, error)
+	CreateCoupon(ctx context.Context, itemName string, coupon model.Coupon) (*model.Coupon, error)
+	ListCoupons(ctx context.Context) ([]model.Coupon, error)
 }
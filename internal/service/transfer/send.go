@@ -0,0 +1,47 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"merchio/internal/metrics"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"strings"
+)
+
+func (s *serv) SendCoins(ctx context.Context, fromID int, toUser string, amount int) (err error) {
+	ctx, span := tracing.Start(ctx, "transfer.SendCoins", attribute.Int("coins.amount", amount))
+	defer tracing.End(span, &err)
+
+	toUser = strings.TrimSpace(toUser)
+	if toUser == "" {
+		return service.ErrReceiverNotFound
+	}
+	if amount <= 0 {
+		return service.ErrInvalidAmount
+	}
+
+	// Менеджер повторяет перевод, если он попал во взаимоблокировку со встречным
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.transferRepository.TransferCoins(ctx, uint(fromID), toUser, amount)
+		return err
+	})
+	switch {
+	case errors.Is(err, repository.ErrRecipientNotFound):
+		return service.ErrReceiverNotFound
+	case errors.Is(err, repository.ErrSelfTransfer):
+		return service.ErrSelfTransfer
+	case errors.Is(err, repository.ErrInsufficientCoins):
+		return service.ErrInsufficientCoins
+	case err != nil:
+		slog.ErrorContext(ctx, "coin transfer failed", "to_user", toUser, "amount", amount, "error", err)
+		return err
+	}
+
+	metrics.ObserveTransfer(amount)
+	slog.InfoContext(ctx, "coins sent", "to_user", toUser, "amount", amount)
+	return nil
+}
@@ -0,0 +1,120 @@
+package transfer
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) TransferCoins(ctx context.Context, fromID uint, toUsername string, amount int) (*model.CoinTransaction, error) {
+	args := m.Called(ctx, fromID, toUsername, amount)
+	transaction, _ := args.Get(0).(*model.CoinTransaction)
+	return transaction, args.Error(1)
+}
+
+func (m *MockTransferRepository) ListHistory(ctx context.Context, userID uint) (*model.CoinHistory, error) {
+	args := m.Called(ctx, userID)
+	history, _ := args.Get(0).(*model.CoinHistory)
+	return history, args.Error(1)
+}
+
+// MockTxManager выполняет fn без транзакции
+type MockTxManager struct {
+	calls int
+}
+
+func (m *MockTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func TestSendCoins_Success(t *testing.T) {
+	transferRepo := new(MockTransferRepository)
+	txManager := new(MockTxManager)
+	s := NewService(transferRepo, txManager)
+
+	transferRepo.On("TransferCoins", mock.Anything, uint(1), "bob", 150).
+		Return(&model.CoinTransaction{ID: 9, FromID: 1, ToID: 2, Amount: 150}, nil)
+
+	err := s.SendCoins(context.Background(), 1, " bob ", 150)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, txManager.calls)
+	transferRepo.AssertExpectations(t)
+}
+
+func TestSendCoins_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		toUser      string
+		amount      int
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:        "Пустой получатель",
+			toUser:      "",
+			amount:      10,
+			expectedErr: service.ErrReceiverNotFound,
+		},
+		{
+			name:        "Нулевая сумма",
+			toUser:      "bob",
+			amount:      0,
+			expectedErr: service.ErrInvalidAmount,
+		},
+		{
+			name:        "Отрицательная сумма",
+			toUser:      "bob",
+			amount:      -5,
+			expectedErr: service.ErrInvalidAmount,
+		},
+		{
+			name:        "Получатель не найден",
+			toUser:      "ghost",
+			amount:      10,
+			repoErr:     repository.ErrRecipientNotFound,
+			expectedErr: service.ErrReceiverNotFound,
+		},
+		{
+			name:        "Перевод самому себе",
+			toUser:      "alice",
+			amount:      10,
+			repoErr:     repository.ErrSelfTransfer,
+			expectedErr: service.ErrSelfTransfer,
+		},
+		{
+			name:        "Недостаточно монет",
+			toUser:      "bob",
+			amount:      5000,
+			repoErr:     repository.ErrInsufficientCoins,
+			expectedErr: service.ErrInsufficientCoins,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transferRepo := new(MockTransferRepository)
+			s := NewService(transferRepo, new(MockTxManager))
+			if tt.repoErr != nil {
+				transferRepo.On("TransferCoins", mock.Anything, uint(1), tt.toUser, tt.amount).Return(nil, tt.repoErr)
+			}
+
+			err := s.SendCoins(context.Background(), 1, tt.toUser, tt.amount)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.repoErr == nil {
+				transferRepo.AssertNotCalled(t, "TransferCoins", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
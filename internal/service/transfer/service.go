@@ -0,0 +1,18 @@
+package transfer
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	transferRepository repository.TransferRepository
+	txManager          repository.TxManager
+}
+
+func NewService(transferRepository repository.TransferRepository, txManager repository.TxManager) service.TransferService {
+	return &serv{
+		transferRepository: transferRepository,
+		txManager:          txManager,
+	}
+}
@@ -3,34 +3,124 @@ package user
 import (
 	"context"
 	"errors"
-	"golang.org/x/crypto/bcrypt"
-	"merchio/internal/utils"
+	"fmt"
+	"log/slog"
+	"merchio/internal/metrics"
+	"merchio/internal/model"
+	"merchio/internal/passhash"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
 )
 
-func (s *serv) Auth(ctx context.Context, username, password string) (string, error) {
-	// Получаем пользователя из БД
+func (s *serv) Auth(ctx context.Context, username, password, ip string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "user.Auth")
+	defer tracing.End(span, &err)
+
+	// Заблокированную пару имя+IP отклоняем до хеширования: пароль не проверяется, даже если он верный
+	attempts, err := s.attemptRepository.Get(ctx, username, ip)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return "", fmt.Errorf("get login attempts: %w", err)
+	}
+	if attempts != nil && attempts.Locked(s.now()) {
+		metrics.ObserveLogin(false)
+		return "", service.ErrAccountLocked
+	}
+
 	present, err := s.userRepository.IsUserPresent(ctx, username)
 	if err != nil {
-		return "", errors.New("ошибка существования пользователя")
+		return "", fmt.Errorf("check user exists: %w", err)
 	}
+	// При первом входе пользователь создаётся автоматически, пароль сохраняется хешем
 	if !present {
-		_, err = s.userRepository.CreateUser(ctx, username, password)
+		// Требования к имени и сложности пароля действуют только при регистрации, старые входы не ломаем
+		errs := service.FieldErrors{}
+		errs.Add("username", service.CheckUsername(username))
+		errs.Add("password", service.CheckPasswordStrength(password))
+		if err := errs.Err(); err != nil {
+			return "", err
+		}
+		_, err := s.CreateUser(ctx, username, password)
+		// Параллельный первый вход мог успеть создать пользователя, тогда просто проверяем пароль
+		if err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+			return "", fmt.Errorf("create user: %w", err)
+		}
 	}
 	user, err := s.userRepository.GetUserByUsername(ctx, username)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("get user: %w", err)
 	}
 
 	// Проверяем пароль
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", errors.New("неверный пароль")
+	if err := s.hasher.Verify(user.Password, password); err != nil {
+		if !errors.Is(err, passhash.ErrMismatch) {
+			return "", fmt.Errorf("verify password: %w", err)
+		}
+		metrics.ObserveLogin(false)
+		return "", s.recordFailure(ctx, username, ip)
+	}
+
+	if attempts != nil {
+		if err := s.attemptRepository.Reset(ctx, username, ip); err != nil {
+			return "", fmt.Errorf("reset login attempts: %w", err)
+		}
+	}
+	// Пароль известен только сейчас, поэтому старые хеши пересчитываются при входе.
+	// Неудача не мешает входу: хеш обновится в следующий раз.
+	if s.hasher.NeedsRehash(user.Password) {
+		if err := s.rehash(ctx, user.ID, password); err != nil {
+			slog.WarnContext(ctx, "password rehash failed", "error", err)
+		}
 	}
 
 	// Генерируем JWT токен
-	token, err := utils.GenerateToken(user.ID)
+	token, err := s.generateToken(user.ID, user.Role)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("generate token: %w", err)
 	}
 
+	metrics.ObserveLogin(true)
 	return token, nil
 }
+
+func (s *serv) rehash(ctx context.Context, userID int, password string) error {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	return s.userRepository.UpdatePassword(ctx, userID, hash)
+}
+
+// recordFailure учитывает неудачный вход и при превышении порога блокирует пару имя+IP
+func (s *serv) recordFailure(ctx context.Context, username, ip string) error {
+	now := s.now()
+	attempts, err := s.attemptRepository.RecordFailure(ctx, username, ip, now, now.Add(-s.lockout.MaxLockout))
+	if err != nil {
+		return fmt.Errorf("record login failure: %w", err)
+	}
+	lockFor := s.lockout.lockFor(attempts.Failures)
+	if lockFor == 0 {
+		return service.ErrInvalidCredentials
+	}
+
+	// Неудача уже учтена, а блокировка без записи в журнале не сохраняется:
+	// следующая неудача снова превысит порог и заблокирует пару
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.attemptRepository.Lock(ctx, username, ip, now.Add(lockFor)); err != nil {
+			return fmt.Errorf("lock login: %w", err)
+		}
+		return s.audit(ctx, model.AuditEntry{
+			Action:   model.AuditLoginLocked,
+			Username: username,
+			IP:       ip,
+			Details:  fmt.Sprintf("%d failed attempts, locked for %s", attempts.Failures, lockFor),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	metrics.ObserveLockout()
+	slog.WarnContext(ctx, "login locked", "username", username, "ip", ip,
+		"failures", attempts.Failures, "locked_for", lockFor.String())
+	return service.ErrAccountLocked
+}
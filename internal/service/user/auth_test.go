@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"merchio/internal/model"
+	"merchio/internal/passhash"
+	"merchio/internal/repository"
+	"merchio/internal/service"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,6 +34,15 @@ func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username str
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	return m.Called(ctx, id, passwordHash).Error(0)
+}
+
 //
 //func (m *MockUserRepository) IsUserPresent(ctx context.Context, username string) (bool, error) {
 //	args := m.Called(ctx, username)
@@ -55,11 +68,101 @@ func (m *MockUtils) GenerateToken(userID int) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Get(ctx context.Context, username, ip string) (*model.LoginAttempts, error) {
+	args := m.Called(ctx, username, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginAttempts), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, username, ip string, now, since time.Time) (*model.LoginAttempts, error) {
+	args := m.Called(ctx, username, ip, now, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoginAttempts), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, username, ip string, until time.Time) error {
+	return m.Called(ctx, username, ip, until).Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, username, ip string) error {
+	return m.Called(ctx, username, ip).Error(0)
+}
+
+func (m *MockLoginAttemptRepository) UnlockUser(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Record(ctx context.Context, entry model.AuditEntry) error {
+	return m.Called(ctx, entry).Error(0)
+}
+
+// MockTxManager выполняет fn без транзакции и запоминает, с какой ошибкой она закончилась
+type MockTxManager struct {
+	calls int
+	err   error
+}
+
+func (m *MockTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.err = fn(ctx)
+	return m.err
+}
+
+const testIP = "10.0.0.1"
+
+var (
+	testNow    = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	testPolicy = LockoutPolicy{MaxFailures: 3, Lockout: time.Minute, MaxLockout: 10 * time.Minute}
+)
+
+// newTestService - сервис без прошлых неудачных входов, неудачи только считаются
+func newTestService(repo *MockUserRepository, utils *MockUtils) *serv {
+	attempts := new(MockLoginAttemptRepository)
+	attempts.On("Get", mock.Anything, mock.Anything, testIP).Return(nil, repository.ErrNotFound).Maybe()
+	attempts.On("RecordFailure", mock.Anything, mock.Anything, testIP, testNow, testNow.Add(-testPolicy.MaxLockout)).
+		Return(&model.LoginAttempts{Failures: 1}, nil).Maybe()
+	return newLockoutTestService(repo, attempts, new(MockAuditRepository), utils)
+}
+
+// testHasher - bcrypt минимальной стоимости: тестовые хеши не пересчитываются, а новые считаются быстро
+var testHasher = passhash.New(passhash.Bcrypt{Cost: bcrypt.MinCost}, testArgon2id)
+
+// testArgon2id - Argon2id с малыми параметрами, чтобы тесты не тратили 64 МиБ на хеш
+var testArgon2id = passhash.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func newLockoutTestService(repo *MockUserRepository, attempts *MockLoginAttemptRepository, audit *MockAuditRepository, utils *MockUtils) *serv {
+	return &serv{
+		userRepository:    repo,
+		attemptRepository: attempts,
+		auditRepository:   audit,
+		txManager:         new(MockTxManager),
+		lockout:           testPolicy,
+		hasher:            testHasher,
+		generateToken: func(userID int, role string) (string, error) {
+			return utils.GenerateToken(userID)
+		},
+		now: func() time.Time { return testNow },
+	}
+}
+
 func TestAuth_Success(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
 	mockUtils := new(MockUtils)
-	s := &serv{userRepository: mockRepo}
+	s := newTestService(mockRepo, mockUtils)
 
 	username := "testuser"
 	password := "password123"
@@ -71,7 +174,7 @@ func TestAuth_Success(t *testing.T) {
 	mockUtils.On("GenerateToken", 1).Return("valid-token", nil)
 
 	// Вызов функции
-	token, err := s.Auth(context.Background(), username, password)
+	token, err := s.Auth(context.Background(), username, password, testIP)
 
 	// Проверки
 	assert.NoError(t, err)
@@ -85,32 +188,136 @@ func TestAuth_Success(t *testing.T) {
 func TestAuth_UserNotFound(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
-	//mockUtils := new(MockUtils)
-	s := &serv{userRepository: mockRepo}
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
 
 	username := "testuser"
 	password := "password123"
+	created := &model.User{ID: 1, Username: username, Role: model.RoleUser}
 
-	// Создаем моки
+	// Нового пользователя создаём с хешем пароля, а не с самим паролем
 	mockRepo.On("IsUserPresent", mock.Anything, username).Return(false, nil)
+	mockRepo.On("CreateUser", mock.Anything, username, mock.MatchedBy(func(hash string) bool {
+		created.Password = hash
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	})).Return(int64(1), nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, username).Return(created, nil)
+	mockUtils.On("GenerateToken", 1).Return("new-user-token", nil)
 
 	// Вызов функции
-	token, err := s.Auth(context.Background(), username, password)
+	token, err := s.Auth(context.Background(), username, password, testIP)
 
 	// Проверки
-	assert.Error(t, err)
-	assert.Empty(t, token)
-	assert.Equal(t, "ошибка существования пользователя", err.Error())
+	assert.NoError(t, err)
+	assert.Equal(t, "new-user-token", token)
+	assert.NotEqual(t, password, created.Password)
 
 	// Убедимся, что моки были вызваны
 	mockRepo.AssertExpectations(t)
+	mockUtils.AssertExpectations(t)
+}
+
+func TestAuth_ConcurrentFirstLogin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(false, nil)
+	mockRepo.On("CreateUser", mock.Anything, "testuser", mock.Anything).Return(int64(0), repository.ErrAlreadyExists)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	mockUtils.On("GenerateToken", 1).Return("valid-token", nil)
+
+	token, err := s.Auth(context.Background(), "testuser", "password123", testIP)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
+}
+
+func TestAuth_WeakPasswordOnSignup(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		expected string
+	}{
+		{name: "Короткий пароль", password: "abc12", expected: "must be at least 8 characters"},
+		{name: "Без цифр", password: "password", expected: "must contain a letter and a digit"},
+		{name: "Без букв", password: "12345678", expected: "must contain a letter and a digit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			s := newTestService(mockRepo, new(MockUtils))
+
+			mockRepo.On("IsUserPresent", mock.Anything, "newbie").Return(false, nil)
+
+			token, err := s.Auth(context.Background(), "newbie", tt.password, testIP)
+
+			assert.Empty(t, token)
+			var fields service.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+			assert.Equal(t, tt.expected, fields["password"])
+			assert.Equal(t, service.KindValidation, service.KindOf(err))
+			mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuth_InvalidUsernameOnSignup(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	s := newTestService(mockRepo, new(MockUtils))
+
+	mockRepo.On("IsUserPresent", mock.Anything, "юл").Return(false, nil)
+
+	token, err := s.Auth(context.Background(), "юл", "passw0rd", testIP)
+
+	assert.Empty(t, token)
+	var fields service.FieldErrors
+	assert.ErrorAs(t, err, &fields)
+	assert.Equal(t, "may contain only latin letters, digits, '_', '-' and '.'", fields["username"])
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuth_InvalidUsernameExistingUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
+
+	// Имя не подходит под правила регистрации, но пользователь уже есть - вход разрешён
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("passw0rd"), bcrypt.MinCost)
+	mockRepo.On("IsUserPresent", mock.Anything, "Юлия").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "Юлия").Return(&model.User{ID: 3, Password: string(hashedPassword)}, nil)
+	mockUtils.On("GenerateToken", 3).Return("valid-token", nil)
+
+	token, err := s.Auth(context.Background(), "Юлия", "passw0rd", testIP)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
+}
+
+func TestAuth_WeakPasswordExistingUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
+
+	// Пользователь зарегистрирован до введения правил, войти он по-прежнему может
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("qwerty"), bcrypt.MinCost)
+	mockRepo.On("IsUserPresent", mock.Anything, "oldtimer").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "oldtimer").Return(&model.User{ID: 2, Password: string(hashedPassword)}, nil)
+	mockUtils.On("GenerateToken", 2).Return("valid-token", nil)
+
+	token, err := s.Auth(context.Background(), "oldtimer", "qwerty", testIP)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
 }
 
 func TestAuth_IncorrectPassword(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
-	//mockUtils := new(MockUtils)
-	s := &serv{userRepository: mockRepo}
+	s := newTestService(mockRepo, new(MockUtils))
 
 	username := "testuser"
 	password := "password123"
@@ -121,12 +328,13 @@ func TestAuth_IncorrectPassword(t *testing.T) {
 	mockRepo.On("GetUserByUsername", mock.Anything, username).Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
 
 	// Вызов функции
-	token, err := s.Auth(context.Background(), username, password)
+	token, err := s.Auth(context.Background(), username, password, testIP)
 
 	// Проверки
 	assert.Error(t, err)
 	assert.Empty(t, token)
-	assert.Equal(t, "неверный пароль", err.Error())
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	assert.Equal(t, service.KindUnauthorized, service.KindOf(err))
 
 	// Убедимся, что моки были вызваны
 	mockRepo.AssertExpectations(t)
@@ -136,7 +344,7 @@ func TestAuth_GenerateTokenError(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
 	mockUtils := new(MockUtils)
-	s := &serv{userRepository: mockRepo}
+	s := newTestService(mockRepo, mockUtils)
 
 	username := "testuser"
 	password := "password123"
@@ -148,12 +356,12 @@ func TestAuth_GenerateTokenError(t *testing.T) {
 	mockUtils.On("GenerateToken", 1).Return("", errors.New("ошибка генерации токена"))
 
 	// Вызов функции
-	token, err := s.Auth(context.Background(), username, password)
+	token, err := s.Auth(context.Background(), username, password, testIP)
 
 	// Проверки
 	assert.Error(t, err)
 	assert.Empty(t, token)
-	assert.Equal(t, "ошибка генерации токена", err.Error())
+	assert.ErrorContains(t, err, "ошибка генерации токена")
 
 	// Убедимся, что моки были вызваны
 	mockRepo.AssertExpectations(t)
@@ -163,8 +371,7 @@ func TestAuth_GenerateTokenError(t *testing.T) {
 func TestAuth_DBError(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
-	//mockUtils := new(MockUtils)
-	s := &serv{userRepository: mockRepo}
+	s := newTestService(mockRepo, new(MockUtils))
 
 	username := "testuser"
 	password := "password123"
@@ -173,12 +380,13 @@ func TestAuth_DBError(t *testing.T) {
 	mockRepo.On("IsUserPresent", mock.Anything, username).Return(false, errors.New("ошибка БД"))
 
 	// Вызов функции
-	token, err := s.Auth(context.Background(), username, password)
+	token, err := s.Auth(context.Background(), username, password, testIP)
 
 	// Проверки
 	assert.Error(t, err)
 	assert.Empty(t, token)
-	assert.Equal(t, "ошибка существования пользователя", err.Error())
+	assert.ErrorContains(t, err, "ошибка БД")
+	assert.Equal(t, service.KindInternal, service.KindOf(err))
 
 	// Убедимся, что моки были вызваны
 	mockRepo.AssertExpectations(t)
@@ -2,23 +2,17 @@ package user
 
 import (
 	"context"
-	"golang.org/x/crypto/bcrypt"
+	"merchio/internal/tracing"
 )
 
-func (s *serv) CreateUser(ctx context.Context, username, password string) (int64, error) {
+func (s *serv) CreateUser(ctx context.Context, username, password string) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "user.CreateUser")
+	defer tracing.End(span, &err)
 
-	hashedPassword, err := hashPassword(password)
+	hashedPassword, err := s.hasher.Hash(password)
 	if err != nil {
 		return 0, err
 	}
 	id, err := s.userRepository.CreateUser(ctx, username, hashedPassword)
 	return id, err
 }
-
-func hashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hash), nil
-}
@@ -13,7 +13,7 @@ import (
 func TestCreateUser_Success(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
-	s := &serv{userRepository: mockRepo}
+	s := &serv{userRepository: mockRepo, hasher: testHasher}
 
 	username := "testuser"
 	password := "password123"
@@ -60,14 +60,17 @@ func TestCreateUser_Success(t *testing.T) {
 func TestCreateUser_CreateUserError(t *testing.T) {
 	// Инициализация моков
 	mockRepo := new(MockUserRepository)
-	s := &serv{userRepository: mockRepo}
+	s := &serv{userRepository: mockRepo, hasher: testHasher}
 
 	username := "testuser"
 	password := "password123"
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	// bcrypt каждый раз солит по-новому, поэтому сверяем не строку, а то, что это хеш пароля
+	isHash := mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	})
 
 	// Мокаем ошибку при создании пользователя
-	mockRepo.On("CreateUser", mock.Anything, username, string(hashedPassword)).Return(int64(0), errors.New("ошибка создания пользователя"))
+	mockRepo.On("CreateUser", mock.Anything, username, isHash).Return(int64(0), errors.New("ошибка создания пользователя"))
 
 	// Вызов функции
 	id, err := s.CreateUser(context.Background(), username, password)
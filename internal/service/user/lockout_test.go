@@ -0,0 +1,193 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLockoutPolicy_LockFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		expected time.Duration
+	}{
+		{name: "До порога", failures: 2, expected: 0},
+		{name: "Порог", failures: 3, expected: time.Minute},
+		{name: "Следующая неудача удваивает срок", failures: 4, expected: 2 * time.Minute},
+		{name: "Ещё одна", failures: 5, expected: 4 * time.Minute},
+		{name: "Не больше максимума", failures: 20, expected: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, testPolicy.lockFor(tt.failures))
+		})
+	}
+}
+
+func TestAuth_LockedSkipsPasswordCheck(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	attempts := new(MockLoginAttemptRepository)
+	s := newLockoutTestService(mockRepo, attempts, new(MockAuditRepository), new(MockUtils))
+
+	lockedUntil := testNow.Add(30 * time.Second)
+	attempts.On("Get", mock.Anything, "testuser", testIP).
+		Return(&model.LoginAttempts{Failures: 3, LockedUntil: &lockedUntil}, nil)
+
+	// Даже верный пароль не проверяется, пока действует блокировка
+	token, err := s.Auth(context.Background(), "testuser", "password123", testIP)
+
+	assert.Empty(t, token)
+	assert.ErrorIs(t, err, service.ErrAccountLocked)
+	assert.Equal(t, service.KindTooManyRequests, service.KindOf(err))
+	mockRepo.AssertNotCalled(t, "GetUserByUsername", mock.Anything, mock.Anything)
+}
+
+func TestAuth_LocksAfterMaxFailures(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	attempts := new(MockLoginAttemptRepository)
+	audit := new(MockAuditRepository)
+	s := newLockoutTestService(mockRepo, attempts, audit, new(MockUtils))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	// Прошлая блокировка истекла, счётчик при этом сохранился
+	expired := testNow.Add(-time.Second)
+	attempts.On("Get", mock.Anything, "testuser", testIP).
+		Return(&model.LoginAttempts{Failures: 3, LockedUntil: &expired}, nil)
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	attempts.On("RecordFailure", mock.Anything, "testuser", testIP, testNow, testNow.Add(-10*time.Minute)).
+		Return(&model.LoginAttempts{Failures: 4}, nil)
+	attempts.On("Lock", mock.Anything, "testuser", testIP, testNow.Add(2*time.Minute)).Return(nil)
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+		return entry.Action == model.AuditLoginLocked && entry.Username == "testuser" && entry.IP == testIP &&
+			entry.ActorID == nil && entry.Details == "4 failed attempts, locked for 2m0s"
+	})).Return(nil)
+
+	token, err := s.Auth(context.Background(), "testuser", "guess", testIP)
+
+	assert.Empty(t, token)
+	assert.ErrorIs(t, err, service.ErrAccountLocked)
+	attempts.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAuth_LockAuditFailureRollsBack(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	attempts := new(MockLoginAttemptRepository)
+	audit := new(MockAuditRepository)
+	tx := new(MockTxManager)
+	s := newLockoutTestService(mockRepo, attempts, audit, new(MockUtils))
+	s.txManager = tx
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	attempts.On("Get", mock.Anything, "testuser", testIP).Return(&model.LoginAttempts{Failures: 2}, nil)
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	attempts.On("RecordFailure", mock.Anything, "testuser", testIP, testNow, mock.Anything).
+		Return(&model.LoginAttempts{Failures: 3}, nil)
+	attempts.On("Lock", mock.Anything, "testuser", testIP, testNow.Add(time.Minute)).Return(nil)
+	audit.On("Record", mock.Anything, mock.Anything).Return(errors.New("ошибка БД"))
+
+	_, err := s.Auth(context.Background(), "testuser", "guess", testIP)
+
+	// Блокировка пишется в одной транзакции с журналом и откатывается вместе с ним
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, service.ErrAccountLocked)
+	assert.Equal(t, 1, tx.calls)
+	assert.Error(t, tx.err)
+	attempts.AssertExpectations(t)
+}
+
+func TestAuth_FailureBelowThreshold(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	attempts := new(MockLoginAttemptRepository)
+	s := newLockoutTestService(mockRepo, attempts, new(MockAuditRepository), new(MockUtils))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	attempts.On("Get", mock.Anything, "testuser", testIP).Return(&model.LoginAttempts{Failures: 1}, nil)
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	attempts.On("RecordFailure", mock.Anything, "testuser", testIP, testNow, mock.Anything).
+		Return(&model.LoginAttempts{Failures: 2}, nil)
+
+	_, err := s.Auth(context.Background(), "testuser", "guess", testIP)
+
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	attempts.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuth_SuccessResetsAttempts(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	attempts := new(MockLoginAttemptRepository)
+	mockUtils := new(MockUtils)
+	s := newLockoutTestService(mockRepo, attempts, new(MockAuditRepository), mockUtils)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	attempts.On("Get", mock.Anything, "testuser", testIP).Return(&model.LoginAttempts{Failures: 2}, nil)
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	attempts.On("Reset", mock.Anything, "testuser", testIP).Return(nil)
+	mockUtils.On("GenerateToken", 1).Return("valid-token", nil)
+
+	token, err := s.Auth(context.Background(), "testuser", "password123", testIP)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
+	attempts.AssertExpectations(t)
+}
+
+func TestUnlockUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		auditErr      error
+		expectedError bool
+	}{
+		{
+			name: "Успешная разблокировка",
+		},
+		{
+			name:          "Ошибка записи в журнал",
+			auditErr:      errors.New("ошибка БД"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := new(MockLoginAttemptRepository)
+			audit := new(MockAuditRepository)
+			tx := new(MockTxManager)
+			s := newLockoutTestService(new(MockUserRepository), attempts, audit, new(MockUtils))
+			s.txManager = tx
+
+			attempts.On("UnlockUser", mock.Anything, "testuser").Return(int64(2), nil)
+			audit.On("Record", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+				return entry.Action == model.AuditLoginUnlocked && entry.Username == "testuser" &&
+					entry.ActorID != nil && *entry.ActorID == 42
+			})).Return(tt.auditErr)
+
+			cleared, err := s.UnlockUser(context.Background(), 42, "testuser")
+
+			assert.Equal(t, 1, tx.calls)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Equal(t, service.KindInternal, service.KindOf(err))
+				// Сброс счётчиков откатывается вместе с несостоявшейся записью в журнал
+				assert.ErrorIs(t, tx.err, tt.auditErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), cleared)
+			audit.AssertExpectations(t)
+		})
+	}
+}
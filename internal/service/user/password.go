@@ -0,0 +1,152 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"merchio/internal/model"
+	"merchio/internal/passhash"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+	"time"
+)
+
+// defaultResetTokenTTL - срок токена сброса, если он не задан в Options
+const defaultResetTokenTTL = time.Hour
+
+func (s *serv) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "user.ChangePassword")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return service.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	// Неверный текущий пароль - ошибка поля, а не 401: токен при этом действителен
+	if err := s.hasher.Verify(user.Password, currentPassword); errors.Is(err, passhash.ErrMismatch) {
+		return service.FieldErrors{"currentPassword": "is incorrect"}
+	} else if err != nil {
+		return fmt.Errorf("verify password: %w", err)
+	}
+	if msg := service.CheckPasswordStrength(newPassword); msg != "" {
+		return service.FieldErrors{"newPassword": msg}
+	}
+	if newPassword == currentPassword {
+		return service.FieldErrors{"newPassword": "must differ from the current password"}
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	// Смена пароля без записи в журнале не сохраняется
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepository.UpdatePassword(ctx, userID, hash); err != nil {
+			return fmt.Errorf("update password: %w", err)
+		}
+		return s.audit(ctx, model.AuditEntry{
+			Action:   model.AuditPasswordChanged,
+			ActorID:  &userID,
+			Username: user.Username,
+		})
+	})
+}
+
+func (s *serv) IssueResetToken(ctx context.Context, adminID int, username string) (_ *model.PasswordResetToken, err error) {
+	ctx, span := tracing.Start(ctx, "user.IssueResetToken")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepository.GetUserByUsername(ctx, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, service.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate reset token: %w", err)
+	}
+	ttl := s.resetTokenTTL
+	if ttl == 0 {
+		ttl = defaultResetTokenTTL
+	}
+	token := &model.PasswordResetToken{
+		Token:     hex.EncodeToString(raw),
+		UserID:    user.ID,
+		CreatedBy: adminID,
+		ExpiresAt: s.now().Add(ttl),
+	}
+	token.TokenHash = hashResetToken(token.Token)
+
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.resetRepository.CreateToken(ctx, *token); err != nil {
+			return fmt.Errorf("create reset token: %w", err)
+		}
+		return s.audit(ctx, model.AuditEntry{
+			Action:   model.AuditPasswordResetIssued,
+			ActorID:  &adminID,
+			Username: user.Username,
+			Details:  "expires at " + token.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *serv) ResetPassword(ctx context.Context, token, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "user.ResetPassword")
+	defer tracing.End(span, &err)
+
+	// Сложность проверяется до того, как токен будет погашен
+	if msg := service.CheckPasswordStrength(newPassword); msg != "" {
+		return service.FieldErrors{"newPassword": msg}
+	}
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Если журнал не записался, токен остаётся непогашенным, а пароль прежним
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		userID, err := s.resetRepository.ResetPassword(ctx, hashResetToken(token), hash, s.now())
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, model.AuditEntry{
+			Action:  model.AuditPasswordReset,
+			ActorID: &userID,
+		})
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return service.ErrResetTokenInvalid
+	}
+	if err != nil {
+		return fmt.Errorf("reset password: %w", err)
+	}
+	return nil
+}
+
+// hashResetToken - в базе хранится только SHA-256 токена: у токена 256 бит случайности, соль не нужна
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *serv) audit(ctx context.Context, entry model.AuditEntry) error {
+	if err := s.auditRepository.Record(ctx, entry); err != nil {
+		return fmt.Errorf("audit %s: %w", entry.Action, err)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/passhash"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) CreateToken(ctx context.Context, token model.PasswordResetToken) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *MockPasswordResetRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) (int, error) {
+	args := m.Called(ctx, tokenHash, passwordHash, now)
+	return args.Int(0), args.Error(1)
+}
+
+// matchesPassword проверяет, что в репозиторий ушёл хеш нужного пароля, а не сам пароль
+func matchesPassword(password string) interface{} {
+	return mock.MatchedBy(func(hash string) bool {
+		return testHasher.Verify(hash, password) == nil
+	})
+}
+
+func TestAuth_RehashesWeakCost(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
+	s.hasher = passhash.New(passhash.Bcrypt{Cost: bcrypt.MinCost + 1})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	mockRepo.On("UpdatePassword", mock.Anything, 1, mock.MatchedBy(func(hash string) bool {
+		cost, _ := bcrypt.Cost([]byte(hash))
+		return cost == bcrypt.MinCost+1 && bcrypt.CompareHashAndPassword([]byte(hash), []byte("password123")) == nil
+	})).Return(errors.New("ошибка БД"))
+	mockUtils.On("GenerateToken", 1).Return("valid-token", nil)
+
+	// Ошибка пересчёта хеша не мешает входу
+	token, err := s.Auth(context.Background(), "testuser", "password123", testIP)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuth_MigratesBcryptToArgon2id(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
+	s.hasher = passhash.New(testArgon2id, passhash.Bcrypt{Cost: bcrypt.MinCost})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: string(hashedPassword)}, nil)
+	mockRepo.On("UpdatePassword", mock.Anything, 1, mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$") && testArgon2id.Verify(hash, "password123") == nil
+	})).Return(nil)
+	mockUtils.On("GenerateToken", 1).Return("valid-token", nil)
+
+	token, err := s.Auth(context.Background(), "testuser", "password123", testIP)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-token", token)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuth_UnknownHashFormat(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUtils := new(MockUtils)
+	s := newTestService(mockRepo, mockUtils)
+
+	mockRepo.On("IsUserPresent", mock.Anything, "testuser").Return(true, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 1, Password: "password123"}, nil)
+
+	// Нераспознанный хеш - поломка данных, а не неверный пароль: попытка не засчитывается
+	_, err := s.Auth(context.Background(), "testuser", "password123", testIP)
+
+	assert.ErrorIs(t, err, passhash.ErrUnknownFormat)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangePassword(t *testing.T) {
+	current, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+	tests := []struct {
+		name           string
+		current        string
+		newPassword    string
+		expectedFields service.FieldErrors
+	}{
+		{
+			name:        "Успешная смена",
+			current:     "password123",
+			newPassword: "n3w-password",
+		},
+		{
+			name:           "Неверный текущий пароль",
+			current:        "password124",
+			newPassword:    "n3w-password",
+			expectedFields: service.FieldErrors{"currentPassword": "is incorrect"},
+		},
+		{
+			name:           "Слабый новый пароль",
+			current:        "password123",
+			newPassword:    "short",
+			expectedFields: service.FieldErrors{"newPassword": "must be at least 8 characters"},
+		},
+		{
+			name:           "Пароль не меняется",
+			current:        "password123",
+			newPassword:    "password123",
+			expectedFields: service.FieldErrors{"newPassword": "must differ from the current password"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			audit := new(MockAuditRepository)
+			s := newLockoutTestService(mockRepo, new(MockLoginAttemptRepository), audit, new(MockUtils))
+
+			mockRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Username: "testuser", Password: string(current)}, nil)
+			mockRepo.On("UpdatePassword", mock.Anything, 1, matchesPassword(tt.newPassword)).Return(nil)
+			audit.On("Record", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+				return entry.Action == model.AuditPasswordChanged && *entry.ActorID == 1 && entry.Username == "testuser"
+			})).Return(nil)
+
+			err := s.ChangePassword(context.Background(), 1, tt.current, tt.newPassword)
+
+			if tt.expectedFields != nil {
+				var fields service.FieldErrors
+				assert.ErrorAs(t, err, &fields)
+				assert.Equal(t, tt.expectedFields, fields)
+				mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePassword_AuditFailureRollsBack(t *testing.T) {
+	current, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	mockRepo := new(MockUserRepository)
+	audit := new(MockAuditRepository)
+	tx := new(MockTxManager)
+	s := newLockoutTestService(mockRepo, new(MockLoginAttemptRepository), audit, new(MockUtils))
+	s.txManager = tx
+
+	mockRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1, Username: "testuser", Password: string(current)}, nil)
+	mockRepo.On("UpdatePassword", mock.Anything, 1, matchesPassword("n3w-password")).Return(nil)
+	audit.On("Record", mock.Anything, mock.Anything).Return(errors.New("ошибка БД"))
+
+	err := s.ChangePassword(context.Background(), 1, "password123", "n3w-password")
+
+	// Новый хеш пишется в той же транзакции, что и журнал, и откатывается вместе с ним
+	assert.Error(t, err)
+	assert.Equal(t, 1, tx.calls)
+	assert.Equal(t, err, tx.err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIssueResetToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	audit := new(MockAuditRepository)
+	resets := new(MockPasswordResetRepository)
+	s := newLockoutTestService(mockRepo, new(MockLoginAttemptRepository), audit, new(MockUtils))
+	s.resetRepository = resets
+	s.resetTokenTTL = 30 * time.Minute
+
+	var stored model.PasswordResetToken
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&model.User{ID: 5, Username: "testuser"}, nil)
+	resets.On("CreateToken", mock.Anything, mock.MatchedBy(func(token model.PasswordResetToken) bool {
+		stored = token
+		return true
+	})).Return(nil)
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+		return entry.Action == model.AuditPasswordResetIssued && *entry.ActorID == 42 && entry.Username == "testuser"
+	})).Return(nil)
+
+	token, err := s.IssueResetToken(context.Background(), 42, "testuser")
+
+	assert.NoError(t, err)
+	assert.Len(t, token.Token, 64)
+	assert.Equal(t, testNow.Add(30*time.Minute), token.ExpiresAt)
+	// В базу попадает только хеш токена
+	assert.Equal(t, hashResetToken(token.Token), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, token.Token)
+	assert.Equal(t, 5, stored.UserID)
+	assert.Equal(t, 42, stored.CreatedBy)
+	audit.AssertExpectations(t)
+}
+
+func TestIssueResetToken_UnknownUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	s := newTestService(mockRepo, new(MockUtils))
+
+	mockRepo.On("GetUserByUsername", mock.Anything, "ghost").Return((*model.User)(nil), repository.ErrNotFound)
+
+	_, err := s.IssueResetToken(context.Background(), 42, "ghost")
+
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+}
+
+func TestResetPassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		newPassword   string
+		repoErr       error
+		auditErr      error
+		expectedError error
+	}{
+		{
+			name:        "Успешный сброс",
+			newPassword: "n3w-password",
+		},
+		{
+			name:          "Токен использован или просрочен",
+			newPassword:   "n3w-password",
+			repoErr:       repository.ErrNotFound,
+			expectedError: service.ErrResetTokenInvalid,
+		},
+		{
+			name:        "Ошибка записи в журнал",
+			newPassword: "n3w-password",
+			auditErr:    errors.New("ошибка БД"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := new(MockAuditRepository)
+			resets := new(MockPasswordResetRepository)
+			tx := new(MockTxManager)
+			s := newLockoutTestService(new(MockUserRepository), new(MockLoginAttemptRepository), audit, new(MockUtils))
+			s.resetRepository = resets
+			s.txManager = tx
+
+			resets.On("ResetPassword", mock.Anything, hashResetToken("raw-token"), matchesPassword(tt.newPassword), testNow).
+				Return(5, tt.repoErr)
+			audit.On("Record", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+				return entry.Action == model.AuditPasswordReset && *entry.ActorID == 5
+			})).Return(tt.auditErr)
+
+			err := s.ResetPassword(context.Background(), "raw-token", tt.newPassword)
+
+			assert.Equal(t, 1, tx.calls)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				audit.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+				return
+			}
+			if tt.auditErr != nil {
+				// Транзакция завершилась ошибкой: токен и пароль откатываются вместе с журналом
+				assert.ErrorIs(t, err, tt.auditErr)
+				assert.ErrorIs(t, tx.err, tt.auditErr)
+				return
+			}
+			assert.NoError(t, err)
+			resets.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	resets := new(MockPasswordResetRepository)
+	s := newTestService(new(MockUserRepository), new(MockUtils))
+	s.resetRepository = resets
+
+	err := s.ResetPassword(context.Background(), "raw-token", "12345678")
+
+	var fields service.FieldErrors
+	assert.ErrorAs(t, err, &fields)
+	resets.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,16 +1,77 @@
 package user
 
 import (
+	"merchio/internal/passhash"
 	"merchio/internal/repository"
 	"merchio/internal/service"
+	"merchio/internal/utils"
+	"time"
 )
 
+// LockoutPolicy - после MaxFailures неудачных входов подряд пара имя+IP блокируется на Lockout,
+// каждая следующая неудача после окончания блокировки удваивает срок, но не больше MaxLockout.
+// Неудачи старше MaxLockout забываются.
+type LockoutPolicy struct {
+	MaxFailures int
+	Lockout     time.Duration
+	MaxLockout  time.Duration
+}
+
+// lockFor - срок блокировки после failures неудач подряд, 0 - без блокировки
+func (p LockoutPolicy) lockFor(failures int) time.Duration {
+	if p.MaxFailures <= 0 || failures < p.MaxFailures {
+		return 0
+	}
+	d := p.Lockout
+	for i := p.MaxFailures; i < failures && d < p.MaxLockout; i++ {
+		d *= 2
+	}
+	if d > p.MaxLockout {
+		d = p.MaxLockout
+	}
+	return d
+}
+
+type Options struct {
+	Lockout LockoutPolicy
+	// Hasher - хеширование паролей, хеши, для которых NeedsRehash, пересчитываются при входе
+	Hasher passhash.Hasher
+	// ResetTokenTTL - сколько действует токен сброса пароля
+	ResetTokenTTL time.Duration
+}
+
 type serv struct {
-	userRepository repository.UserRepository
+	userRepository    repository.UserRepository
+	attemptRepository repository.LoginAttemptRepository
+	auditRepository   repository.AuditRepository
+	resetRepository   repository.PasswordResetRepository
+	txManager         repository.TxManager
+	lockout           LockoutPolicy
+	hasher            passhash.Hasher
+	resetTokenTTL     time.Duration
+	// generateToken и now подменяются в тестах
+	generateToken func(userID int, role string) (string, error)
+	now           func() time.Time
 }
 
-func NewService(userRepository repository.UserRepository) service.UserService {
+func NewService(
+	userRepository repository.UserRepository,
+	attemptRepository repository.LoginAttemptRepository,
+	auditRepository repository.AuditRepository,
+	resetRepository repository.PasswordResetRepository,
+	txManager repository.TxManager,
+	opts Options,
+) service.UserService {
 	return &serv{
-		userRepository: userRepository,
+		userRepository:    userRepository,
+		attemptRepository: attemptRepository,
+		auditRepository:   auditRepository,
+		resetRepository:   resetRepository,
+		txManager:         txManager,
+		lockout:           opts.Lockout,
+		hasher:            opts.Hasher,
+		resetTokenTTL:     opts.ResetTokenTTL,
+		generateToken:     utils.GenerateToken,
+		now:               time.Now,
 	}
 }
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"merchio/internal/model"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) UnlockUser(ctx context.Context, adminID int, username string) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "user.UnlockUser")
+	defer tracing.End(span, &err)
+
+	// Разблокировка без записи в журнале откатывается
+	var n int64
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		n, err = s.attemptRepository.UnlockUser(ctx, username)
+		if err != nil {
+			return fmt.Errorf("unlock user: %w", err)
+		}
+		return s.audit(ctx, model.AuditEntry{
+			Action:   model.AuditLoginUnlocked,
+			ActorID:  &adminID,
+			Username: username,
+			Details:  fmt.Sprintf("%d login records cleared", n),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	slog.InfoContext(ctx, "login unlocked", "username", username, "admin_id", adminID)
+	return n, nil
+}
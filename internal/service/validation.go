@@ -0,0 +1,93 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	UsernameMinLen = 3
+	UsernameMaxLen = 32
+	PasswordMinLen = 8
+	// PasswordMaxLen - bcrypt учитывает только первые 72 байта
+	PasswordMaxLen = 72
+)
+
+// FieldErrors - ошибки валидации по полям запроса, ключ - имя поля в JSON
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+": "+e[field])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Add запоминает первую ошибку поля, если message не пустой
+func (e FieldErrors) Add(field, message string) {
+	if message == "" {
+		return
+	}
+	if _, ok := e[field]; !ok {
+		e[field] = message
+	}
+}
+
+// Err возвращает nil, если ошибок нет, чтобы не получить непустой интерфейс с пустой картой
+func (e FieldErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// CheckUsername - латиница, цифры, '_', '-' и '.', от 3 до 32 символов
+func CheckUsername(username string) string {
+	if n := len(username); n < UsernameMinLen || n > UsernameMaxLen {
+		return "must be between 3 and 32 characters"
+	}
+	for _, c := range username {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.') {
+			return "may contain only latin letters, digits, '_', '-' and '.'"
+		}
+	}
+	return ""
+}
+
+// CheckPasswordLength - ограничения для любого пароля, в том числе при входе
+func CheckPasswordLength(password string) string {
+	switch {
+	case password == "":
+		return "is required"
+	case len(password) > PasswordMaxLen:
+		return "must not exceed 72 bytes"
+	}
+	return ""
+}
+
+// CheckPasswordStrength - требования к новому паролю: не короче 8 символов, есть буква и цифра
+func CheckPasswordStrength(password string) string {
+	if msg := CheckPasswordLength(password); msg != "" {
+		return msg
+	}
+	if len([]rune(password)) < PasswordMinLen {
+		return "must be at least 8 characters"
+	}
+	var letter, digit bool
+	for _, c := range password {
+		letter = letter || unicode.IsLetter(c)
+		digit = digit || unicode.IsDigit(c)
+	}
+	if !letter || !digit {
+		return "must contain a letter and a digit"
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		valid    bool
+	}{
+		{name: "Обычное имя", username: "alice", valid: true},
+		{name: "Допустимые символы", username: "john.doe_42-x", valid: true},
+		{name: "Слишком короткое", username: "al"},
+		{name: "Слишком длинное", username: "abcdefghijklmnopqrstuvwxyz0123456"},
+		{name: "Пробел", username: "john doe"},
+		{name: "Кириллица", username: "иван"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, CheckUsername(tt.username) == "")
+		})
+	}
+}
+
+func TestCheckPasswordStrength(t *testing.T) {
+	assert.Empty(t, CheckPasswordStrength("passw0rd"))
+	assert.Equal(t, "is required", CheckPasswordStrength(""))
+	assert.Equal(t, "must be at least 8 characters", CheckPasswordStrength("pass1"))
+	assert.Equal(t, "must contain a letter and a digit", CheckPasswordStrength("password"))
+	assert.Equal(t, "must not exceed 72 bytes", CheckPasswordStrength(string(make([]byte, 73))))
+}
+
+func TestFieldErrors(t *testing.T) {
+	errs := FieldErrors{}
+	assert.NoError(t, errs.Err())
+
+	errs.Add("username", "")
+	errs.Add("username", "is required")
+	errs.Add("username", "ignored")
+	errs.Add("amount", "must be positive")
+
+	assert.EqualError(t, errs.Err(), "amount: must be positive; username: is required")
+	assert.Equal(t, KindValidation, KindOf(errs))
+}
@@ -0,0 +1,34 @@
+package wishlist
+
+import (
+	"context"
+	"merchio/internal/model"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) GetWishlist(ctx context.Context, userID int) (_ *model.Wishlist, err error) {
+	ctx, span := tracing.Start(ctx, "wishlist.GetWishlist")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.wishlistRepository.ListItems(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Сколько монет осталось накопить на каждый товар
+	for i := range entries {
+		if needed := entries[i].Item.Price - user.Coins; needed > 0 {
+			entries[i].CoinsNeeded = needed
+		}
+	}
+
+	return &model.Wishlist{
+		Coins: user.Coins,
+		Items: entries,
+	}, nil
+}
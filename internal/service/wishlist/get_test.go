@@ -0,0 +1,125 @@
+package wishlist
+
+import (
+	"context"
+	"merchio/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) IsUserPresent(ctx context.Context, username string) (bool, error) {
+	args := m.Called(ctx, username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, username, password string) (int64, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	args := m.Called(ctx, username)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	return m.Called(ctx, id, passwordHash).Error(0)
+}
+
+type MockMerchRepository struct {
+	mock.Mock
+}
+
+func (m *MockMerchRepository) GetItemByName(ctx context.Context, name string) (*model.MerchItem, error) {
+	args := m.Called(ctx, name)
+	item, _ := args.Get(0).(*model.MerchItem)
+	return item, args.Error(1)
+}
+
+func (m *MockMerchRepository) ListItems(ctx context.Context) ([]model.MerchItem, error) {
+	args := m.Called(ctx)
+	items, _ := args.Get(0).([]model.MerchItem)
+	return items, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpdateItem(ctx context.Context, name string, price, stock int) (*model.MerchItem, *model.MerchItem, error) {
+	args := m.Called(ctx, name, price, stock)
+	before, _ := args.Get(0).(*model.MerchItem)
+	after, _ := args.Get(1).(*model.MerchItem)
+	return before, after, args.Error(2)
+}
+
+func (m *MockMerchRepository) ListVariants(ctx context.Context) ([]model.MerchVariant, error) {
+	args := m.Called(ctx)
+	variants, _ := args.Get(0).([]model.MerchVariant)
+	return variants, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpsertVariant(ctx context.Context, itemName string, variant model.MerchVariant) (*model.MerchVariant, *model.MerchVariant, error) {
+	args := m.Called(ctx, itemName, variant)
+	before, _ := args.Get(0).(*model.MerchVariant)
+	after, _ := args.Get(1).(*model.MerchVariant)
+	return before, after, args.Error(2)
+}
+
+type MockWishlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockWishlistRepository) AddItem(ctx context.Context, userID, merchID uint) error {
+	args := m.Called(ctx, userID, merchID)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) RemoveItem(ctx context.Context, userID, merchID uint) (bool, error) {
+	args := m.Called(ctx, userID, merchID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWishlistRepository) ListItems(ctx context.Context, userID uint) ([]model.WishlistEntry, error) {
+	args := m.Called(ctx, userID)
+	entries, _ := args.Get(0).([]model.WishlistEntry)
+	return entries, args.Error(1)
+}
+
+func (m *MockWishlistRepository) ListUserIDsByMerch(ctx context.Context, merchID uint) ([]uint, error) {
+	args := m.Called(ctx, merchID)
+	ids, _ := args.Get(0).([]uint)
+	return ids, args.Error(1)
+}
+
+func TestGetWishlist_CoinsNeeded(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	s := NewService(userRepo, merchRepo, wishlistRepo)
+
+	userRepo.On("GetUserByID", mock.Anything, 7).Return(&model.User{ID: 7, Coins: 120}, nil)
+	wishlistRepo.On("ListItems", mock.Anything, uint(7)).Return([]model.WishlistEntry{
+		{Item: model.MerchItem{ID: 2, Name: "cup", Price: 20}},
+		{Item: model.MerchItem{ID: 6, Name: "hoody", Price: 300}},
+	}, nil)
+
+	wishlist, err := s.GetWishlist(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 120, wishlist.Coins)
+	assert.Equal(t, 0, wishlist.Items[0].CoinsNeeded)
+	assert.Equal(t, 180, wishlist.Items[1].CoinsNeeded)
+
+	userRepo.AssertExpectations(t)
+	wishlistRepo.AssertExpectations(t)
+}
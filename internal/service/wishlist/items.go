@@ -0,0 +1,50 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"merchio/internal/tracing"
+)
+
+func (s *serv) AddItem(ctx context.Context, userID int, itemName string) (err error) {
+	ctx, span := tracing.Start(ctx, "wishlist.AddItem")
+	defer tracing.End(span, &err)
+
+	item, err := s.getItem(ctx, itemName)
+	if err != nil {
+		return err
+	}
+	return s.wishlistRepository.AddItem(ctx, uint(userID), item.ID)
+}
+
+func (s *serv) RemoveItem(ctx context.Context, userID int, itemName string) (err error) {
+	ctx, span := tracing.Start(ctx, "wishlist.RemoveItem")
+	defer tracing.End(span, &err)
+
+	item, err := s.getItem(ctx, itemName)
+	if err != nil {
+		return err
+	}
+	removed, err := s.wishlistRepository.RemoveItem(ctx, uint(userID), item.ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return service.ErrItemNotFound
+	}
+	return nil
+}
+
+func (s *serv) getItem(ctx context.Context, name string) (*model.MerchItem, error) {
+	item, err := s.merchRepository.GetItemByName(ctx, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, service.ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
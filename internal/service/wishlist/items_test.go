@@ -0,0 +1,55 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"merchio/internal/model"
+	"merchio/internal/repository"
+	"merchio/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddItem_Success(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	s := NewService(new(MockUserRepository), merchRepo, wishlistRepo)
+
+	merchRepo.On("GetItemByName", mock.Anything, "hoody").Return(&model.MerchItem{ID: 6, Name: "hoody"}, nil)
+	wishlistRepo.On("AddItem", mock.Anything, uint(7), uint(6)).Return(nil)
+
+	err := s.AddItem(context.Background(), 7, "hoody")
+
+	assert.NoError(t, err)
+	merchRepo.AssertExpectations(t)
+	wishlistRepo.AssertExpectations(t)
+}
+
+func TestAddItem_UnknownItem(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	s := NewService(new(MockUserRepository), merchRepo, wishlistRepo)
+
+	merchRepo.On("GetItemByName", mock.Anything, "yacht").Return(nil, repository.ErrNotFound)
+
+	err := s.AddItem(context.Background(), 7, "yacht")
+
+	assert.True(t, errors.Is(err, service.ErrItemNotFound))
+	wishlistRepo.AssertNotCalled(t, "AddItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoveItem_NotInWishlist(t *testing.T) {
+	merchRepo := new(MockMerchRepository)
+	wishlistRepo := new(MockWishlistRepository)
+	s := NewService(new(MockUserRepository), merchRepo, wishlistRepo)
+
+	merchRepo.On("GetItemByName", mock.Anything, "cup").Return(&model.MerchItem{ID: 2, Name: "cup"}, nil)
+	wishlistRepo.On("RemoveItem", mock.Anything, uint(7), uint(2)).Return(false, nil)
+
+	err := s.RemoveItem(context.Background(), 7, "cup")
+
+	assert.True(t, errors.Is(err, service.ErrItemNotFound))
+	wishlistRepo.AssertExpectations(t)
+}
@@ -0,0 +1,24 @@
+package wishlist
+
+import (
+	"merchio/internal/repository"
+	"merchio/internal/service"
+)
+
+type serv struct {
+	userRepository     repository.UserRepository
+	merchRepository    repository.MerchRepository
+	wishlistRepository repository.WishlistRepository
+}
+
+func NewService(
+	userRepository repository.UserRepository,
+	merchRepository repository.MerchRepository,
+	wishlistRepository repository.WishlistRepository,
+) service.WishlistService {
+	return &serv{
+		userRepository:     userRepository,
+		merchRepository:    merchRepository,
+		wishlistRepository: wishlistRepository,
+	}
+}
@@ -0,0 +1,247 @@
+//go:build integration
+
+// Package testdb - Postgres для интеграционных тестов (go test -tags integration).
+// Сервер берётся из TEST_POSTGRES_DSN или запускается из локальных бинарников во временный каталог:
+// каталог с initdb и postgres ищется в PG_BIN, PATH и стандартных местах установки. Сеть не нужна.
+// Каждый тест получает свою схему с накатанными миграциями и не видит данных других тестов.
+package testdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"merchio/internal/client/db/pg"
+	"merchio/internal/migrations"
+)
+
+// binCandidates - типичные места установки Postgres в Debian/Ubuntu, RHEL и Homebrew
+var binCandidates = []string{
+	"/usr/lib/postgresql/*/bin",
+	"/usr/pgsql-*/bin",
+	"/usr/local/pgsql/bin",
+	"/opt/homebrew/opt/postgresql*/bin",
+	"/usr/local/opt/postgresql*/bin",
+}
+
+// Server - запущенный или внешний Postgres
+type Server struct {
+	dsn   string
+	admin *pgxpool.Pool
+	cmd   *exec.Cmd
+	// exited закрывается, когда процесс postgres завершился
+	exited chan struct{}
+	dir    string
+}
+
+var (
+	shared     *Server
+	skipReason string
+	schemaSeq  atomic.Int64
+)
+
+// Main поднимает общий сервер на время тестов пакета, вызывается из TestMain.
+// Если Postgres недоступен, тесты пакета пропускаются с объяснением причины.
+func Main(m *testing.M) {
+	srv, err := Start(context.Background())
+	if err != nil {
+		skipReason = "postgres is not available: " + err.Error()
+		fmt.Fprintln(os.Stderr, "testdb:", skipReason)
+	}
+	shared = srv
+
+	code := m.Run()
+	if srv != nil {
+		if err := srv.Stop(); err != nil {
+			fmt.Fprintln(os.Stderr, "testdb: stop postgres:", err)
+		}
+	}
+	os.Exit(code)
+}
+
+// New - пул в отдельной схеме с накатанными миграциями, схема удаляется после теста
+func New(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	if shared == nil {
+		t.Skip(skipReason)
+	}
+	return shared.NewSchema(t)
+}
+
+// Start подключается к TEST_POSTGRES_DSN или запускает локальный сервер
+func Start(ctx context.Context) (*Server, error) {
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		return connect(ctx, &Server{dsn: dsn})
+	}
+
+	bin, err := findBin()
+	if err != nil {
+		return nil, err
+	}
+	// postgres отказывается работать от root, а сменить пользователя без sudo нельзя
+	if os.Geteuid() == 0 {
+		return nil, errors.New("postgres refuses to run as root, set TEST_POSTGRES_DSN instead")
+	}
+
+	dir, err := os.MkdirTemp("", "merchio-pg-")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{dir: dir}
+	if err := s.launch(ctx, bin); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	return connect(ctx, s)
+}
+
+func findBin() (string, error) {
+	if dir := os.Getenv("PG_BIN"); dir != "" {
+		return dir, nil
+	}
+	if path, err := exec.LookPath("initdb"); err == nil {
+		return filepath.Dir(path), nil
+	}
+	for _, pattern := range binCandidates {
+		matches, _ := filepath.Glob(pattern)
+		// Glob сортирует по имени, последняя версия - в конце
+		for i := len(matches) - 1; i >= 0; i-- {
+			if _, err := os.Stat(filepath.Join(matches[i], "initdb")); err == nil {
+				return matches[i], nil
+			}
+		}
+	}
+	return "", errors.New("initdb not found in PG_BIN, PATH or standard locations")
+}
+
+func (s *Server) launch(ctx context.Context, bin string) error {
+	data := filepath.Join(s.dir, "data")
+	initdb := exec.CommandContext(ctx, filepath.Join(bin, "initdb"),
+		"-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--locale=C", "--no-sync")
+	if out, err := initdb.CombinedOutput(); err != nil {
+		return fmt.Errorf("initdb: %w: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+	logFile, err := os.Create(filepath.Join(s.dir, "postgres.log"))
+	if err != nil {
+		return err
+	}
+	// Надёжность не нужна: данные живут до конца тестов
+	s.cmd = exec.Command(filepath.Join(bin, "postgres"),
+		"-D", data, "-p", strconv.Itoa(port), "-k", s.dir,
+		"-c", "listen_addresses=127.0.0.1",
+		"-c", "fsync=off",
+		"-c", "synchronous_commit=off",
+		"-c", "full_page_writes=off",
+		"-c", "max_connections=300")
+	s.cmd.Stdout, s.cmd.Stderr = logFile, logFile
+	if err := s.cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("start postgres: %w", err)
+	}
+	s.exited = make(chan struct{})
+	go func() {
+		s.cmd.Wait()
+		logFile.Close()
+		close(s.exited)
+	}()
+
+	s.dsn = fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port)
+	return nil
+}
+
+func freePort() (int, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port, nil
+}
+
+// connect дожидается готовности сервера, свежий postgres принимает соединения не сразу
+func connect(ctx context.Context, s *Server) (*Server, error) {
+	db, err := pg.Connect(ctx, s.dsn, pg.Options{ConnectAttempts: 10, ConnectBackoff: 100 * time.Millisecond})
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+	s.admin = db.Pool()
+	return s, nil
+}
+
+// DSN - строка подключения к базе сервера без привязки к схеме
+func (s *Server) DSN() string {
+	return s.dsn
+}
+
+// NewSchema создаёт схему, накатывает в неё миграции и возвращает пул, в котором она первая в search_path
+func (s *Server) NewSchema(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	schema := fmt.Sprintf("test_%d_%d", os.Getpid(), schemaSeq.Add(1))
+
+	if _, err := s.admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := s.admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	db, err := pg.Connect(ctx, s.dsn, pg.Options{
+		MaxConns: 20,
+		Configure: func(cfg *pgxpool.Config) {
+			cfg.ConnConfig.RuntimeParams["search_path"] = schema
+		},
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	// Пул закрывается раньше, чем удаляется схема: Cleanup выполняются в обратном порядке
+	t.Cleanup(db.Close)
+
+	loaded, err := migrations.Load(migrations.Files)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrations.NewMigrator(db.Pool(), loaded).Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db.Pool()
+}
+
+// Stop останавливает запущенный сервер и удаляет его каталог, внешний сервер не трогает
+func (s *Server) Stop() error {
+	if s.admin != nil {
+		s.admin.Close()
+	}
+	if s.exited == nil {
+		return os.RemoveAll(s.dir)
+	}
+
+	// SIGINT - быстрая остановка postgres: активные транзакции откатываются
+	s.cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-s.exited:
+	case <-time.After(10 * time.Second):
+		s.cmd.Process.Kill()
+		<-s.exited
+	}
+	return os.RemoveAll(s.dir)
+}
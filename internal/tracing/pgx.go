@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// В pgx v4 нет хуков начала и конца запроса, но логгер получает каждый запрос
+// уже после выполнения вместе с длительностью. По ним спан восстанавливается задним числом.
+
+// queryLogger превращает записи pgx о запросах в спаны. Аргументы запросов в спаны не попадают.
+type queryLogger struct{}
+
+// InstrumentPool включает SQL-спаны для всех соединений пула
+func InstrumentPool(cfg *pgxpool.Config) {
+	cfg.ConnConfig.Logger = queryLogger{}
+	cfg.ConnConfig.LogLevel = pgx.LogLevelInfo
+}
+
+func (queryLogger) Log(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+	sql, ok := data["sql"].(string)
+	if !ok && msg != "SendBatch" {
+		return
+	}
+	duration, _ := data["time"].(time.Duration)
+	end := time.Now()
+
+	operation := strings.ToUpper(strings.SplitN(strings.TrimSpace(sql), " ", 2)[0])
+	if msg == "SendBatch" {
+		operation = "BATCH"
+	}
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationName(operation),
+	}
+	if sql != "" {
+		attrs = append(attrs, semconv.DBQueryText(sql))
+	}
+	if n, ok := data["rowCount"].(int); ok {
+		attrs = append(attrs, attribute.Int("db.rows_returned", n))
+	}
+	if tag, ok := data["commandTag"].(pgconn.CommandTag); ok {
+		attrs = append(attrs, attribute.Int64("db.rows_affected", tag.RowsAffected()))
+	}
+	if n, ok := data["batchLen"].(int); ok {
+		attrs = append(attrs, attribute.Int("db.batch_size", n))
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(end.Add(-duration)),
+		trace.WithAttributes(attrs...))
+	if err, ok := data["err"].(error); ok && level <= pgx.LogLevelError {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
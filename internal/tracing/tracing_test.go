@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans подменяет глобальный провайдер на время теста
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(NewProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddleware_NamesSpanByRoute(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/buy/{item}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := Start(r.Context(), "inventory.BuyItem")
+		span.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/buy/cup", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /api/buy/{item}", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "/api/buy/{item}", attr(server, "http.route").AsString())
+	assert.Equal(t, int64(500), attr(server, "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Error, server.Status().Code)
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+}
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	_, err := Setup(context.Background(), Options{Exporter: ExporterNone})
+	require.NoError(t, err)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	recorder := recordSpans(t)
+
+	func() (err error) {
+		_, span := Start(context.Background(), "transfer.SendCoins")
+		defer End(span, &err)
+		return errors.New("insufficient coins")
+	}()
+	func() (err error) {
+		_, span := Start(context.Background(), "inventory.GetInfo")
+		defer End(span, &err)
+		return nil
+	}()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestQueryLogger(t *testing.T) {
+	recorder := recordSpans(t)
+	ctx, parent := Start(context.Background(), "user.Auth")
+
+	queryLogger{}.Log(ctx, pgx.LogLevelInfo, "Query", map[string]interface{}{
+		"sql":      "SELECT id FROM users WHERE username = $1",
+		"args":     []interface{}{"alice"},
+		"time":     3 * time.Millisecond,
+		"rowCount": 1,
+	})
+	queryLogger{}.Log(ctx, pgx.LogLevelInfo, "Exec", map[string]interface{}{
+		"sql":        "UPDATE users SET coins = coins - $1 WHERE id = $2",
+		"time":       time.Millisecond,
+		"commandTag": pgconn.CommandTag("UPDATE 1"),
+	})
+	queryLogger{}.Log(ctx, pgx.LogLevelError, "Exec", map[string]interface{}{
+		"sql":  "INSERT INTO users (username) VALUES ($1)",
+		"time": time.Millisecond,
+		"err":  errors.New("duplicate key"),
+	})
+	queryLogger{}.Log(ctx, pgx.LogLevelInfo, "closed connection", nil)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	query, update, insert := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "db SELECT", query.Name())
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, int64(1), attr(query, "db.rows_returned").AsInt64())
+	assert.Equal(t, 3*time.Millisecond, query.EndTime().Sub(query.StartTime()))
+	for _, kv := range query.Attributes() {
+		assert.NotContains(t, kv.Value.Emit(), "alice", "query arguments must not leak into spans")
+	}
+
+	assert.Equal(t, "db UPDATE", update.Name())
+	assert.Equal(t, int64(1), attr(update, "db.rows_affected").AsInt64())
+
+	assert.Equal(t, "db INSERT", insert.Name())
+	assert.Equal(t, codes.Error, insert.Status().Code)
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Options{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
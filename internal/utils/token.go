@@ -13,20 +13,22 @@ const (
 
 type UserClaims struct {
 	jwt.StandardClaims
-	UserId int `json:"user_id"`
+	UserId int    `json:"user_id"`
+	Role   string `json:"role"`
 }
 
-func GenerateToken(userId int) (string, error) {
+func GenerateToken(userId int, role string) (string, error) {
 	claims := &UserClaims{
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
 		},
 		UserId: userId,
+		Role:   role,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString(accessTokenSecretKey)
+	return token.SignedString([]byte(accessTokenSecretKey))
 }
 
 // Проверка токена
@@ -41,7 +43,7 @@ func VerifyToken(tokenString string) (*UserClaims, error) {
 				return nil, errors.Errorf("unexpected token signing method")
 			}
 
-			return accessTokenSecretKey, nil
+			return []byte(accessTokenSecretKey), nil
 		},
 	)
 	if err != nil {
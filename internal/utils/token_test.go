@@ -8,17 +8,26 @@ import (
 func TestGenerateAndVerifyToken(t *testing.T) {
 	tests := []struct {
 		name        string
-		userID      uint64
+		userID      int
+		role        string
 		shouldError bool
 	}{
 		{
 			name:        "Успешная генерация и проверка токена",
 			userID:      1,
+			role:        "user",
 			shouldError: false,
 		},
 		{
 			name:        "Генерация токена с нулевым ID",
 			userID:      0,
+			role:        "user",
+			shouldError: false,
+		},
+		{
+			name:        "Токен администратора",
+			userID:      2,
+			role:        "admin",
 			shouldError: false,
 		},
 	}
@@ -26,7 +35,7 @@ func TestGenerateAndVerifyToken(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Генерация токена
-			token, err := GenerateToken(tt.userID)
+			token, err := GenerateToken(tt.userID, tt.role)
 			if tt.shouldError {
 				assert.Error(t, err)
 				return
@@ -39,6 +48,7 @@ func TestGenerateAndVerifyToken(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, claims)
 			assert.Equal(t, tt.userID, claims.UserId)
+			assert.Equal(t, tt.role, claims.Role)
 		})
 	}
 }